@@ -0,0 +1,139 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlLink   = regexp.MustCompile(`(?s)<a\s[^>]*href="(https?://[^"]+)"[^>]*>(.*?)</a>`)
+	htmlTag    = regexp.MustCompile(`<[^>]*>`)
+	whitespace = regexp.MustCompile(`\s+`)
+
+	htmlElement = regexp.MustCompile(`<(/?)(\w+)([^>]*)>`)
+	htmlHref    = regexp.MustCompile(`href="([^"]*)"`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// docsOrigin resolves the relative links in swagger.json.
+const docsOrigin = "https://finnhub.io"
+
+// stripHTML turns a swagger description into plain text: external links
+// become "text (url)", other tags are dropped (keeping their inner text),
+// entities are decoded and whitespace collapsed.
+func stripHTML(s string) string {
+	s = htmlLink.ReplaceAllString(s, "$2 ($1)")
+	s = htmlTag.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	s = whitespace.ReplaceAllString(s, " ")
+	// Dropping tags can leave a space before punctuation: "<code>x</code>."
+	for _, p := range []string{".", ",", ")", ":", ";"} {
+		s = strings.ReplaceAll(s, " "+p, p)
+	}
+	s = strings.ReplaceAll(s, "( ", "(")
+	return strings.TrimSpace(s)
+}
+
+// markdown turns a swagger description into markdown: paragraphs and lists
+// keep their structure, tables become pipe tables (lists inside a cell are
+// joined with commas), code and links keep their formatting, and relative
+// links and anchors point at the Finnhub website.
+func markdown(s string) string {
+	var (
+		out    strings.Builder
+		cell   *strings.Builder // Cell being read, if inside <td> or <th>
+		row    []string
+		rows   int
+		href   string
+		inLink bool
+	)
+	w := func(text string) {
+		if cell != nil {
+			cell.WriteString(text)
+		} else {
+			out.WriteString(text)
+		}
+	}
+
+	pos := 0
+	for _, m := range htmlElement.FindAllStringSubmatchIndex(s, -1) {
+		w(whitespace.ReplaceAllString(html.UnescapeString(s[pos:m[0]]), " "))
+		pos = m[1]
+		closing, name, attrs := s[m[2]:m[3]] == "/", strings.ToLower(s[m[4]:m[5]]), s[m[6]:m[7]]
+
+		switch name {
+		case "p", "br", "ul", "ol", "table":
+			if cell != nil {
+				w(" ")
+			} else {
+				w("\n\n")
+			}
+			if name == "table" {
+				rows = 0
+			}
+		case "li":
+			switch {
+			case closing:
+			case cell == nil:
+				w("\n- ")
+			case strings.TrimSpace(cell.String()) != "":
+				text := strings.TrimRight(cell.String(), " ")
+				cell.Reset()
+				cell.WriteString(text + ", ")
+			}
+		case "code":
+			w("`")
+		case "i", "em":
+			w("*")
+		case "b", "strong":
+			w("**")
+		case "a":
+			if closing {
+				if inLink {
+					w("](" + href + ")")
+				}
+				inLink = false
+				break
+			}
+			if h := htmlHref.FindStringSubmatch(attrs); h != nil {
+				href, inLink = h[1], true
+				switch {
+				case strings.HasPrefix(href, "/"):
+					href = docsOrigin + href
+				case strings.HasPrefix(href, "#"):
+					// Anchors name other endpoints of the documentation
+					href = docsOrigin + "/docs/api/" + href[1:]
+				}
+				w("[")
+			}
+		case "tr":
+			if !closing {
+				row = nil
+				break
+			}
+			out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+			if rows == 0 {
+				out.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+			}
+			rows++
+		case "td", "th":
+			// The documentation sometimes closes a <td> with </th>
+			if closing {
+				if cell != nil {
+					row = append(row, strings.TrimSpace(whitespace.ReplaceAllString(cell.String(), " ")))
+				}
+				cell = nil
+			} else {
+				cell = &strings.Builder{}
+			}
+		}
+	}
+	w(whitespace.ReplaceAllString(html.UnescapeString(s[pos:]), " "))
+
+	lines := strings.Split(out.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(whitespace.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// tool is everything the templates need to emit one tool file.
+type tool struct {
+	Name        string // MCP tool name, e.g. get_stock_candle
+	File        string // Output file, e.g. stock_candles.go
+	Prefix      string // Go identifier prefix, e.g. Stock_candles
+	Method      string
+	Path        string
+	Idempotent  bool   // A non-GET request that is safe to retry
+	Title       string // Human-readable title, e.g. Stock Candles
+	Description string // Markdown
+	Toolset     string // Slug of the documentation section, e.g. stock-price
+	Tier        string // plan.Tier constant, e.g. Premium
+	AccessNote  string // The premium or freeTier note, e.g. "Premium Access Required"
+	AccessVar   string // Variable holding the tool's plan.Access
+
+	QueryParams  []queryParam
+	ObjectParams []objectParam // Object arguments flattened into the query string
+	Body         *objectParam  // Object argument sent as the JSON request body
+	Schemas      []schemaVar   // Schema variables declared in this file
+	Imports      []string      // Hand-written packages the overrides use
+
+	OutputSchema  string      // Constant holding the output schema, if the spec has one
+	OutputSchemas []schemaVar // Output schema constants declared in this file
+}
+
+type queryParam struct {
+	Name        string
+	Type        string
+	Format      string // Only "date" (YYYY-MM-DD) is checked
+	Description string
+	Required    bool
+	Enum        []any
+	Minimum     *float64
+	Maximum     *float64
+}
+
+type objectParam struct {
+	Name        string
+	Ident       string // Go variable holding the argument
+	SchemaVar   string // Schema variable, or Go expression for an override
+	Description string // Go expression
+	Check       string // Optional extra validation, a Go expression of type error
+	Required    bool
+}
+
+type schemaVar struct {
+	Name       string
+	Definition string
+	Literal    string
+}
+
+// buildTools turns every operation in the spec into a tool, ordered the way
+// the Finnhub documentation lists them.
+func buildTools(s *spec) ([]tool, error) {
+	var tools []tool
+	declared := map[string]bool{}
+	params := map[string]bool{}
+	for _, path := range orderedPaths(s) {
+		ops := s.Paths[path]
+		for _, method := range slices.Sorted(maps.Keys(ops)) {
+			t, err := buildTool(s, path, method, ops[method], declared)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			tools = append(tools, t)
+			for _, p := range ops[method].Parameters {
+				params[t.Name+"."+p.Name] = true
+			}
+		}
+	}
+	// Catch overrides left behind when the spec renames a tool or parameter.
+	for _, key := range slices.Sorted(maps.Keys(paramOverrides)) {
+		if !params[key] {
+			return nil, fmt.Errorf("override %s matches no parameter", key)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(idempotentTools)) {
+		if !slices.ContainsFunc(tools, func(t tool) bool { return t.Name == name && t.Method != "GET" }) {
+			return nil, fmt.Errorf("idempotent tool %s matches no non-GET tool", name)
+		}
+	}
+	return tools, nil
+}
+
+// orderedPaths follows the spec's "orders" list and appends any remaining
+// paths alphabetically.
+func orderedPaths(s *spec) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, p := range s.Orders {
+		if _, ok := s.Paths[p]; ok && !seen[p] {
+			paths = append(paths, p)
+			seen[p] = true
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(s.Paths)) {
+		if !seen[p] {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func buildTool(s *spec, path, method string, op operation, declared map[string]bool) (tool, error) {
+	base := strings.ReplaceAll(op.OperationID, "-", "_")
+	t := tool{
+		Name:        method + "_" + strings.ReplaceAll(strings.TrimPrefix(path, "/"), "/", "_"),
+		File:        base + ".go",
+		Prefix:      string(unicode.ToUpper(rune(base[0]))) + base[1:],
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Title:       op.Summary,
+		Description: markdown(op.Description),
+		Toolset:     slug(op.Section),
+	}
+	if t.Description == "" {
+		t.Description = op.Summary
+	}
+	if t.Toolset == "" {
+		return tool{}, fmt.Errorf("operation has no section")
+	}
+	t.Idempotent = t.Method != "GET" && idempotentTools[t.Name]
+	tier, ok := tiers[op.Premium]
+	if !ok {
+		return tool{}, fmt.Errorf("unknown premium note %q", op.Premium)
+	}
+	t.Tier, t.AccessNote, t.AccessVar = tier, op.Premium, lowerCamel(base)+"Access"
+	if op.Premium == "" {
+		t.AccessNote = op.FreeTier
+	}
+	if t.Method != http.MethodGet && t.Method != http.MethodPost {
+		return tool{}, fmt.Errorf("unsupported method")
+	}
+
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "query":
+			t.QueryParams = append(t.QueryParams, buildQueryParam(t.Name, p))
+		case "body":
+			obj, err := buildObjectParam(s, &t, p, declared)
+			if err != nil {
+				return tool{}, err
+			}
+			if t.Method == http.MethodGet {
+				// GET endpoints cannot carry a body; their object
+				// arguments are sent as individual query parameters.
+				t.ObjectParams = append(t.ObjectParams, obj)
+			} else {
+				if t.Body != nil {
+					return tool{}, fmt.Errorf("more than one body parameter")
+				}
+				t.Body = &obj
+			}
+		default:
+			return tool{}, fmt.Errorf("unsupported parameter location %q for %s", p.In, p.Name)
+		}
+	}
+	if err := buildOutputSchema(s, &t, op, declared); err != nil {
+		return tool{}, err
+	}
+	return t, nil
+}
+
+func buildQueryParam(toolName string, p parameter) queryParam {
+	q := queryParam{
+		Name:        p.Name,
+		Type:        p.paramType(),
+		Description: markdown(p.Description),
+		Required:    p.Required,
+		Enum:        p.Enum,
+	}
+	// Several date parameters only say so in their description.
+	if q.Type == "string" && (p.Format == "date" || strings.Contains(p.Description, "YYYY-MM-DD")) {
+		q.Format = "date"
+	}
+	if o, ok := paramOverrides[toolName+"."+p.Name]; ok {
+		if o.Enum != nil {
+			q.Enum = o.Enum
+		}
+		if o.Format != "" {
+			q.Format = o.Format
+		}
+		q.Minimum, q.Maximum = o.Minimum, o.Maximum
+	}
+	return q
+}
+
+func buildObjectParam(s *spec, t *tool, p parameter, declared map[string]bool) (objectParam, error) {
+	if p.Schema == nil {
+		return objectParam{}, fmt.Errorf("body parameter %s has no schema", p.Name)
+	}
+	def, defName, err := s.resolve(*p.Schema)
+	if err != nil {
+		return objectParam{}, err
+	}
+	obj := objectParam{
+		Name:        p.Name,
+		Ident:       lowerCamel(p.Name),
+		SchemaVar:   lowerCamel(defName) + "Schema",
+		Description: fmt.Sprintf("%q", markdown(p.Description)),
+		Required:    p.Required || len(def.Required) > 0,
+	}
+
+	if o, ok := paramOverrides[t.Name+"."+p.Name]; ok {
+		// Free-form definitions are backed by a hand-written schema.
+		obj.SchemaVar = o.Schema
+		obj.Description = o.Description
+		obj.Check = o.Check
+		if !slices.Contains(t.Imports, o.Import) {
+			t.Imports = append(t.Imports, o.Import)
+		}
+		return obj, nil
+	}
+	if len(def.Properties) == 0 {
+		return objectParam{}, fmt.Errorf("body parameter %s: definition %s has no properties and no override", p.Name, defName)
+	}
+	if !declared[obj.SchemaVar] {
+		lit, err := goSchema(s, def, 0)
+		if err != nil {
+			return objectParam{}, err
+		}
+		t.Schemas = append(t.Schemas, schemaVar{Name: obj.SchemaVar, Definition: defName, Literal: lit})
+		declared[obj.SchemaVar] = true
+	}
+	return obj, nil
+}
+
+// goSchema renders a definition as a Go map literal for the schema package.
+// Objects are closed with additionalProperties: false so typos are reported.
+func goSchema(s *spec, def schemaDef, depth int) (string, error) {
+	def, _, err := s.resolve(def)
+	if err != nil {
+		return "", err
+	}
+	indent := strings.Repeat("\t", depth)
+	var b strings.Builder
+	b.WriteString("map[string]any{\n")
+	fmt.Fprintf(&b, "%s\t%q: %q,\n", indent, "type", def.Type)
+	if d := stripHTML(def.Description); d != "" {
+		fmt.Fprintf(&b, "%s\t%q: %q,\n", indent, "description", d)
+	}
+	if len(def.Enum) > 0 {
+		fmt.Fprintf(&b, "%s\t%q: %s,\n", indent, "enum", goValues(def.Enum))
+	}
+	switch def.Type {
+	case "object":
+		if len(def.Required) > 0 {
+			fmt.Fprintf(&b, "%s\t%q: []any{%s},\n", indent, "required", quoteAll(def.Required))
+		}
+		fmt.Fprintf(&b, "%s\t%q: map[string]any{\n", indent, "properties")
+		for _, name := range slices.Sorted(maps.Keys(def.Properties)) {
+			lit, err := goSchema(s, def.Properties[name], depth+2)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s\t\t%q: %s,\n", indent, name, lit)
+		}
+		fmt.Fprintf(&b, "%s\t},\n", indent)
+		fmt.Fprintf(&b, "%s\t%q: false,\n", indent, "additionalProperties")
+	case "array":
+		if def.Items != nil {
+			lit, err := goSchema(s, *def.Items, depth+1)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s\t%q: %s,\n", indent, "items", lit)
+		}
+	}
+	b.WriteString(indent + "}")
+	return b.String(), nil
+}
+
+func goValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			parts[i] = fmt.Sprintf("%q", str)
+		} else {
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	return "[]any{" + strings.Join(parts, ", ") + "}"
+}
+
+func quoteAll(values []string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// toolset is a documentation section, rendered into the registry.
+type toolset struct {
+	Name  string
+	Title string
+}
+
+// tiers maps the premium notes in swagger.json to plan.Tier constants.
+// Operations without a note are free, possibly with a freeTier limit.
+var tiers = map[string]string{
+	"":                        "Free",
+	"Premium":                 "Premium",
+	"Premium required.":       "Premium",
+	"Premium Access Required": "Premium",
+	"Accessible with Fundamental data or All in One subscription.": "Fundamental",
+	"Accessible with Fundamental or All in One subscription.":      "Fundamental",
+	"Accessible with Fundamental 2 or All in One subscription.":    "Fundamental2",
+	"Access approved on a case by case basis":                      "Enterprise",
+}
+
+// buildToolsets lists the sections in the order their first tool appears.
+func buildToolsets(s *spec, tools []tool) []toolset {
+	titles := map[string]string{}
+	for _, ops := range s.Paths {
+		for _, op := range ops {
+			titles[slug(op.Section)] = op.Section
+		}
+	}
+	var sets []toolset
+	seen := map[string]bool{}
+	for _, t := range tools {
+		if !seen[t.Toolset] {
+			sets = append(sets, toolset{Name: t.Toolset, Title: titles[t.Toolset]})
+			seen[t.Toolset] = true
+		}
+	}
+	return sets
+}
+
+// slug turns a section title into a toolset name: "ETFs & Indices" ->
+// "etfs-indices".
+func slug(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+}
+
+// lowerCamel lower-cases the leading capitals of a name, keeping the last
+// one of an acronym that starts a new word: AIChatBody -> aiChatBody,
+// indicator_fields -> indicatorFields, LastBid-Ask -> lastBidAsk.
+func lowerCamel(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	r := []rune(strings.Join(parts, ""))
+	for i := range r {
+		if !unicode.IsUpper(r[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// typePair matches the two-element type lists of nullable fields, which
+// json.MarshalIndent spreads over four lines.
+var typePair = regexp.MustCompile(`\[\s+("\w+"),\s+("\w+")\s+\]`)
+
+// buildOutputSchema derives the tool's output schema from the 200 response
+// of op. Operations without a response schema (file downloads) get none.
+func buildOutputSchema(s *spec, t *tool, op operation, declared map[string]bool) error {
+	resp, ok := op.Responses["200"]
+	if !ok || resp.Schema == nil {
+		return nil
+	}
+	def, defName, err := s.resolve(*resp.Schema)
+	if err != nil {
+		return err
+	}
+
+	label := defName
+	var root map[string]any
+	switch def.Type {
+	case "object", "":
+		root, err = outputSchema(s, def, false)
+		if err != nil {
+			return err
+		}
+		root["type"] = "object"
+	case "array":
+		// Structured content must be an object, so arrays are wrapped the
+		// way result.Structured wraps them at runtime.
+		if def.Items == nil {
+			return fmt.Errorf("array response without items")
+		}
+		item, itemName, err := s.resolve(*def.Items)
+		if err != nil {
+			return err
+		}
+		if itemName == "" {
+			itemName = item.Type
+		}
+		defName = itemName + "List"
+		label = itemName + " arrays"
+		items, err := outputSchema(s, def, true)
+		if err != nil {
+			return err
+		}
+		root = map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"items": items},
+		}
+	default:
+		return fmt.Errorf("unsupported response type %q", def.Type)
+	}
+
+	t.OutputSchema = lowerCamel(defName) + "OutputSchema"
+	if declared[t.OutputSchema] {
+		return nil
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(data), "`") {
+		return fmt.Errorf("output schema %s contains a backquote", defName)
+	}
+	data = typePair.ReplaceAll(data, []byte("[$1, $2]"))
+	t.OutputSchemas = append(t.OutputSchemas, schemaVar{Name: t.OutputSchema, Definition: label, Literal: "`" + string(data) + "`"})
+	declared[t.OutputSchema] = true
+	return nil
+}
+
+// outputSchema renders a response definition as JSON Schema. Finnhub leaves
+// fields out or sends null when it has no data, so nothing is required and
+// nested values are nullable. Swagger formats such as "float" are not JSON
+// Schema formats and are dropped.
+func outputSchema(s *spec, def schemaDef, nullable bool) (map[string]any, error) {
+	def, _, err := s.resolve(def)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{}
+	if def.Type != "" {
+		if nullable {
+			out["type"] = []string{def.Type, "null"}
+		} else {
+			out["type"] = def.Type
+		}
+	}
+	if d := stripHTML(def.Description); d != "" {
+		out["description"] = d
+	}
+	if len(def.Properties) > 0 {
+		props := map[string]any{}
+		for name, prop := range def.Properties {
+			if props[name], err = outputSchema(s, prop, true); err != nil {
+				return nil, err
+			}
+		}
+		out["properties"] = props
+	}
+	if def.Items != nil {
+		if out["items"], err = outputSchema(s, *def.Items, true); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+// paramOverride supplies what swagger.json cannot express for a parameter.
+// The expressions refer to a hand-written package outside the generated
+// directory.
+type paramOverride struct {
+	Import      string // Import path of the package the expressions use
+	Schema      string // Go expression for the parameter schema
+	Description string // Go expression for the parameter description
+	Check       string // Optional Go expression of type error; args and val are in scope
+
+	// Constraints for query parameters that the spec only states in prose.
+	Enum    []any
+	Minimum *float64
+	Maximum *float64
+	Format  string
+}
+
+// paramOverrides is keyed by "<tool name>.<parameter name>".
+var paramOverrides = map[string]paramOverride{
+	// IndicatorFields is a free-form object in the spec; the supported
+	// parameters per indicator live in internal/indicator.
+	"get_indicator.indicator_fields": {
+		Import:      "github.com/finnhub-api/mcp-server/internal/indicator",
+		Schema:      "indicator.Schema",
+		Description: "indicator.Description()",
+		Check:       `indicator.Check(fmt.Sprint(args["indicator"]), val)`,
+	},
+
+	"get_news.category":        {Enum: []any{"general", "forex", "crypto", "merger"}},
+	"get_news.minId":           {Minimum: ptr(0)},
+	"get_stock_peers.grouping": {Enum: []any{"sector", "industry", "subIndustry"}},
+	"get_stock_metric.metric":  {Enum: []any{"all"}},
+
+	"get_stock_financials.statement":        {Enum: []any{"bs", "ic", "cf"}},
+	"get_stock_financials.freq":             {Enum: []any{"annual", "quarterly", "ttm", "ytd"}},
+	"get_stock_financials-reported.freq":    {Enum: annualQuarterly},
+	"get_stock_similarity-index.freq":       {Enum: annualQuarterly},
+	"get_stock_revenue-estimate.freq":       {Enum: annualQuarterly},
+	"get_stock_ebitda-estimate.freq":        {Enum: annualQuarterly},
+	"get_stock_ebit-estimate.freq":          {Enum: annualQuarterly},
+	"get_stock_eps-estimate.freq":           {Enum: annualQuarterly},
+	"get_stock_earnings-quality-score.freq": {Enum: annualQuarterly},
+	"get_stock_price-metric.date":           {Format: "date"},
+
+	"get_stock_candle.resolution":             {Enum: resolutions},
+	"get_forex_candle.resolution":             {Enum: resolutions},
+	"get_crypto_candle.resolution":            {Enum: resolutions},
+	"get_scan_pattern.resolution":             {Enum: resolutions},
+	"get_scan_support-resistance.resolution":  {Enum: resolutions},
+	"get_scan_technical-indicator.resolution": {Enum: resolutions},
+	"get_indicator.resolution":                {Enum: resolutions},
+
+	"get_stock_ownership.limit":      {Minimum: ptr(1)},
+	"get_stock_fund-ownership.limit": {Minimum: ptr(1)},
+	"get_stock_earnings.limit":       {Minimum: ptr(1)},
+	"get_stock_tick.limit":           {Minimum: ptr(1), Maximum: ptr(25000)},
+	"get_stock_tick.skip":            {Minimum: ptr(0)},
+	"get_stock_bbo.limit":            {Minimum: ptr(1), Maximum: ptr(25000)},
+	"get_stock_bbo.skip":             {Minimum: ptr(0)},
+	"get_bond_tick.limit":            {Minimum: ptr(1), Maximum: ptr(25000)},
+	"get_bond_tick.skip":             {Minimum: ptr(0)},
+	"get_bond_tick.exchange":         {Enum: []any{"trace"}},
+	"get_etf_holdings.skip":          {Minimum: ptr(0)},
+	"get_etf_holdings.date":          {Format: "date"},
+	"get_mutual-fund_holdings.skip":  {Minimum: ptr(0)},
+	"get_forex_rates.date":           {Format: "date"},
+
+	"get_airline_price-index.airline": {Enum: []any{
+		"united", "delta", "american_airlines", "southwest", "southern_airways_express",
+		"alaska_airlines", "frontier_airlines", "jetblue_airways", "spirit_airlines",
+		"sun_country_airlines", "breeze_airways", "hawaiian_airlines",
+	}},
+}
+
+// idempotentTools lists the non-GET tools whose requests are safe to retry
+// and to share between identical concurrent calls. These endpoints only
+// search filings; they use POST for their JSON request body. Any other
+// non-GET tool, such as the AI chat, is sent once.
+var idempotentTools = map[string]bool{
+	"post_global-filings_search":           true,
+	"post_global-filings_search-in-filing": true,
+}
+
+var (
+	annualQuarterly = []any{"annual", "quarterly"}
+	resolutions     = []any{"1", "5", "15", "30", "60", "D", "W", "M"}
+)
+
+func ptr(v float64) *float64 { return &v }
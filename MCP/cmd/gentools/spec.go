@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// spec is the subset of swagger.json the generator reads.
+type spec struct {
+	Orders      []string                        `json:"orders"`
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]schemaDef            `json:"definitions"`
+}
+
+type operation struct {
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	OperationID string              `json:"operationId"`
+	Section     string              `json:"section"`
+	Premium     string              `json:"premium"`
+	FreeTier    string              `json:"freeTier"`
+	Parameters  []parameter         `json:"parameters"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type response struct {
+	Description string     `json:"description"`
+	Schema      *schemaDef `json:"schema"`
+}
+
+type parameter struct {
+	In          string     `json:"in"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Required    bool       `json:"required"`
+	Type        string     `json:"type"`
+	Format      string     `json:"format"`
+	Enum        []any      `json:"enum"`
+	Schema      *schemaDef `json:"schema"`
+}
+
+type schemaDef struct {
+	Ref         string               `json:"$ref"`
+	Type        string               `json:"type"`
+	Format      string               `json:"format"`
+	Description string               `json:"description"`
+	Enum        []any                `json:"enum"`
+	Required    []string             `json:"required"`
+	Properties  map[string]schemaDef `json:"properties"`
+	Items       *schemaDef           `json:"items"`
+}
+
+func loadSpec(path string) (*spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// resolve follows a local "#/definitions/..." reference.
+func (s *spec) resolve(def schemaDef) (schemaDef, string, error) {
+	if def.Ref == "" {
+		return def, "", nil
+	}
+	name := strings.TrimPrefix(def.Ref, "#/definitions/")
+	resolved, ok := s.Definitions[name]
+	if !ok {
+		return schemaDef{}, "", fmt.Errorf("unknown definition %s", def.Ref)
+	}
+	return resolved, name, nil
+}
+
+// paramType returns the JSON type of a parameter, which is either given
+// directly or, for a few parameters, through an inline schema.
+func (p parameter) paramType() string {
+	if p.Type != "" {
+		return p.Type
+	}
+	if p.Schema != nil && p.Schema.Type != "" {
+		return p.Schema.Type
+	}
+	return "string"
+}
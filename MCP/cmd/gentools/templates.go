@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+	"names": func(params []queryParam) string {
+		quoted := make([]string, len(params))
+		for i, p := range params {
+			quoted[i] = fmt.Sprintf("%q", p.Name)
+		}
+		return strings.Join(quoted, ", ")
+	},
+	"option": paramOption,
+	"header": func() string { return generatedHeader },
+}
+
+// paramOption renders the mcp.With* option declaring a query parameter.
+func paramOption(p queryParam) string {
+	var opts []string
+	if p.Type == "integer" {
+		// mcp-go has no integer property; narrow the number type instead.
+		opts = append(opts, "schema.Integer()")
+	}
+	if p.Required {
+		opts = append(opts, "mcp.Required()")
+	}
+	if p.Description != "" {
+		opts = append(opts, fmt.Sprintf("mcp.Description(%q)", p.Description))
+	}
+	if len(p.Enum) > 0 {
+		values := make([]string, len(p.Enum))
+		for i, v := range p.Enum {
+			values[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+		}
+		opts = append(opts, "mcp.Enum("+strings.Join(values, ", ")+")")
+	}
+	if p.Minimum != nil {
+		opts = append(opts, fmt.Sprintf("mcp.Min(%v)", *p.Minimum))
+	}
+	if p.Maximum != nil {
+		opts = append(opts, fmt.Sprintf("mcp.Max(%v)", *p.Maximum))
+	}
+	if p.Format != "" {
+		opts = append(opts, fmt.Sprintf("schema.Format(%q)", p.Format))
+	}
+
+	with := "mcp.WithString"
+	switch p.Type {
+	case "integer", "number":
+		with = "mcp.WithNumber"
+	case "boolean":
+		with = "mcp.WithBoolean"
+	}
+	return fmt.Sprintf("%s(%s)", with, strings.Join(append([]string{fmt.Sprintf("%q", p.Name)}, opts...), ", "))
+}
+
+var toolTemplate = template.Must(template.New("tool").Funcs(funcs).Parse(`{{header}}
+
+package tools
+
+import (
+	"context"
+{{- if .Body}}
+	"encoding/json"
+{{- end}}
+{{- if .ObjectParams}}
+	"fmt"
+{{- end}}
+
+	"github.com/finnhub-api/mcp-server/config"
+{{- range .Imports}}
+	{{quote .}}
+{{- end}}
+{{- if or .QueryParams .ObjectParams}}
+	"github.com/finnhub-api/mcp-server/internal/params"
+{{- end}}
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
+	"github.com/finnhub-api/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+{{range .Schemas}}
+// {{.Name}} mirrors the {{.Definition}} definition in swagger.json.
+var {{.Name}} = {{.Literal}}
+{{end}}
+
+// {{.AccessVar}} is the plan access {{.Name}} requires, from swagger.json.
+var {{.AccessVar}} = plan.Access{Tier: plan.{{.Tier}}{{if .AccessNote}}, Note: {{quote .AccessNote}}{{end}}}
+{{- range .OutputSchemas}}
+// {{.Name}} is the output schema for {{.Definition}}, derived from swagger.json.
+const {{.Name}} = {{.Literal}}
+{{end}}
+func {{.Prefix}}Handler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+{{- if or .QueryParams .ObjectParams .Body}}
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+{{- end}}
+{{- if or .QueryParams .ObjectParams}}
+		query := params.Query(args{{if .QueryParams}}, {{names .QueryParams}}{{end}})
+{{- end}}
+{{- range .ObjectParams}}
+		if val, ok := args[{{quote .Name}}].(map[string]any); ok {
+{{- if .Check}}
+			if err := {{.Check}}; err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid {{.Name}} argument: %v", err)), nil
+			}
+{{- end}}
+			params.Merge(query, val)
+		}
+{{- end}}
+{{- with .Body}}
+		{{.Ident}}, ok := args[{{quote .Name}}]
+		if !ok {
+			return mcp.NewToolResultError("Missing required argument: {{.Name}}"), nil
+		}
+		requestBody, err := json.Marshal({{.Ident}})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
+		}
+{{- end}}
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: {{quote .Name}}, Method: {{quote .Method}}, Path: {{quote .Path}}
+{{- if or .QueryParams .ObjectParams}}, Query: query{{end}}
+{{- if .Body}}, Body: requestBody{{end}}
+{{- if .Idempotent}}, Idempotent: true{{end}}})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		}
+
+		if resp.StatusCode >= 400 {
+			return result.Error(ctx, cfg, {{quote .Name}}, {{.AccessVar}}, resp), nil
+		}
+		return result.JSON(resp.Body), nil
+	}
+}
+
+func Create{{.Prefix}}Tool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool({{quote .Name}},
+		mcp.WithDescription({{quote .Description}}),
+		mcp.WithTitleAnnotation({{quote .Title}}),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+{{- if .OutputSchema}}
+		mcp.WithRawOutputSchema([]byte({{.OutputSchema}})),
+{{- end}}
+{{- range .QueryParams}}
+		{{option .}},
+{{- end}}
+{{- range .ObjectParams}}
+		mcp.WithObject({{quote .Name}}, schema.Property({{.SchemaVar}}){{if .Required}}, mcp.Required(){{end}}, mcp.Description({{.Description}})),
+{{- end}}
+{{- with .Body}}
+		mcp.WithObject({{quote .Name}}, schema.Property({{.SchemaVar}}){{if .Required}}, mcp.Required(){{end}}, mcp.Description({{.Description}})),
+{{- end}}
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    schema.Checked(tool, {{.Prefix}}Handler(cfg)),
+		Toolset:    {{quote .Toolset}},
+		Access:     {{.AccessVar}},
+	}
+}
+`))
+
+var registryTemplate = template.Must(template.New("registry").Funcs(funcs).Parse(`{{header}}
+
+package main
+
+import (
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/models"
+	tools_default "github.com/finnhub-api/mcp-server/tools/default"
+)
+
+func GetAll(cfg *config.APIConfig) []models.Tool {
+	return []models.Tool{
+{{- range .Tools}}
+		tools_default.Create{{.Prefix}}Tool(cfg),
+{{- end}}
+	}
+}
+
+// Toolsets lists the tool groups, one per section of the Finnhub API
+// documentation.
+var Toolsets = []models.Toolset{
+{{- range .Toolsets}}
+	{Name: {{quote .Name}}, Title: {{quote .Title}}},
+{{- end}}
+}
+`))
@@ -0,0 +1,363 @@
+// Package completions suggests values for prompt and resource template
+// arguments: stock symbols, crypto and forex pairs, exchanges and economic
+// codes. Suggestions come from Finnhub's reference lists, read through the
+// tools that serve them and cached, so completions answer quickly once a
+// list has been fetched.
+package completions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	maxValues  = 100 // The most values MCP allows in one completion
+	listTTL    = 24 * time.Hour
+	failureTTL = time.Minute // Failed fetches are not retried before this
+	// Entries cached at once, across every list and API key. Each list
+	// also counts as one entry, so cached failures are bounded too.
+	maxEntries = 500_000
+)
+
+// Provider completes arguments by their name, for every prompt and
+// resource template alike:
+//   - symbol: stock symbols, or crypto and forex pairs once an exchange
+//     prefix such as BINANCE: is typed
+//   - exchange: crypto and forex exchanges
+//   - code: economic codes, matched by code or by indicator name
+type Provider struct {
+	tools  map[string]server.ToolHandlerFunc
+	usable func(ctx context.Context, tool string) bool
+
+	mu    sync.Mutex
+	lists map[string]*list // By API key, tool and arguments
+	size  int              // Cost of the cached lists, at most maxEntries
+}
+
+var (
+	_ server.PromptCompletionProvider   = (*Provider)(nil)
+	_ server.ResourceCompletionProvider = (*Provider)(nil)
+)
+
+// New returns a provider that reads reference lists with the given tool
+// handlers, by tool name. Lists are only used for sessions that can call
+// their tool, as reported by usable.
+func New(tools map[string]server.ToolHandlerFunc, usable func(ctx context.Context, tool string) bool) *Provider {
+	return &Provider{tools: tools, usable: usable, lists: map[string]*list{}}
+}
+
+// CompletePromptArgument implements server.PromptCompletionProvider.
+func (p *Provider) CompletePromptArgument(ctx context.Context, prompt string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, argument), nil
+}
+
+// CompleteResourceArgument implements server.ResourceCompletionProvider.
+func (p *Provider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, argument), nil
+}
+
+func (p *Provider) complete(ctx context.Context, argument mcp.CompleteArgument) *mcp.Completion {
+	var values []string
+	switch argument.Name {
+	case "symbol":
+		values = p.symbols(ctx, argument.Value)
+	case "exchange":
+		var exchanges []entry
+		for _, e := range p.exchanges(ctx) {
+			exchanges = append(exchanges, e.entry)
+		}
+		values = match(exchanges, argument.Value)
+	case "code":
+		values = match(p.fetch(ctx, "get_economic_code", nil, objects("code", "name")), argument.Value)
+	}
+	completion := &mcp.Completion{Values: []string{}, Total: len(values), HasMore: len(values) > maxValues}
+	completion.Values = append(completion.Values, values[:min(len(values), maxValues)]...)
+	return completion
+}
+
+// symbols completes stock symbols from the US symbol list, or from the list
+// of the exchange named by a known suffix such as .DE. Typing an exchange prefix
+// such as BINANCE: or OANDA: switches to that exchange's pairs. Values that
+// match no symbol are looked up with get_search, which also matches
+// company names.
+func (p *Provider) symbols(ctx context.Context, value string) []string {
+	upper := strings.ToUpper(value)
+	exchanges := p.exchanges(ctx)
+	if prefix, _, ok := strings.Cut(upper, ":"); ok {
+		var pairs []entry
+		for _, e := range exchanges {
+			if e.key == prefix {
+				pairs = append(pairs, p.fetch(ctx, e.symbolTool, map[string]any{"exchange": e.value}, objects("symbol", "description"))...)
+			}
+		}
+		return match(pairs, value)
+	}
+
+	var values []string
+	for _, e := range exchanges {
+		if value != "" && strings.HasPrefix(e.key, upper) {
+			values = append(values, e.key+":")
+		}
+	}
+	stocks := match(p.fetch(ctx, "get_stock_symbol", map[string]any{"exchange": "US"}, objects("symbol", "description")), value)
+	if i := strings.LastIndex(upper, "."); len(stocks) == 0 && i >= 0 && stockExchanges[upper[i+1:]] {
+		stocks = match(p.fetch(ctx, "get_stock_symbol", map[string]any{"exchange": upper[i+1:]}, objects("symbol", "description")), value)
+	}
+	if len(stocks) == 0 && len(value) >= 2 {
+		stocks = p.search(ctx, value)
+	}
+	return append(values, stocks...)
+}
+
+// exchange is a crypto or forex exchange and the tool listing its pairs.
+type exchange struct {
+	entry
+	symbolTool string
+}
+
+// exchanges returns the crypto and forex exchanges.
+func (p *Provider) exchanges(ctx context.Context) []exchange {
+	var exchanges []exchange
+	for _, source := range []struct{ list, symbols string }{
+		{"get_crypto_exchange", "get_crypto_symbol"},
+		{"get_forex_exchange", "get_forex_symbol"},
+	} {
+		for _, e := range p.fetch(ctx, source.list, nil, names) {
+			exchanges = append(exchanges, exchange{entry: e, symbolTool: source.symbols})
+		}
+	}
+	return exchanges
+}
+
+// search looks a value up with get_search. Results depend on the query, so
+// they are not cached.
+func (p *Provider) search(ctx context.Context, query string) []string {
+	value, err := p.call(ctx, "get_search", map[string]any{"q": query})
+	if err != nil {
+		return nil
+	}
+	found, _ := value["result"].([]any)
+	var symbols []string
+	for _, v := range found {
+		if item, ok := v.(map[string]any); ok {
+			if symbol, ok := item["symbol"].(string); ok && symbol != "" {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	return symbols
+}
+
+// entry is one value of a reference list.
+type entry struct {
+	value string
+	key   string // Upper case value, for matching
+	label string // Lower case description, for matching by name
+}
+
+// list is a cached reference list. Its fields are guarded by Provider.mu.
+type list struct {
+	entries  []entry
+	expires  time.Time     // Zero until fetched
+	fetching chan struct{} // Closed when the fetch in flight ends; nil without one
+}
+
+// cost is what a list counts against maxEntries.
+func (l *list) cost() int { return len(l.entries) + 1 }
+
+// fetch returns a reference list read with tool and args, converted by
+// extract and sorted shortest value first. Lists, and failures to fetch
+// them, are cached per API key, since what Finnhub returns depends on the
+// key's plan. Concurrent requests for a list share one fetch, and stop
+// waiting for it when their own context is done.
+func (p *Provider) fetch(ctx context.Context, tool string, args map[string]any, extract func(map[string]any) []entry) []entry {
+	if !p.usable(ctx, tool) {
+		return nil
+	}
+	key := tool + fmt.Sprint(args)
+	if cfg, ok := config.FromContext(ctx); ok {
+		key = cfg.CredentialKey() + " " + key
+	}
+
+	p.mu.Lock()
+	l, ok := p.lists[key]
+	for ok && l.fetching != nil {
+		done := l.fetching
+		p.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil
+		}
+		p.mu.Lock()
+		l, ok = p.lists[key]
+	}
+	if ok && time.Now().Before(l.expires) {
+		entries := l.entries
+		p.mu.Unlock()
+		return entries
+	}
+	if !ok {
+		l = &list{}
+		p.lists[key] = l
+		p.size += l.cost()
+	}
+	done := make(chan struct{})
+	l.fetching = done
+	p.mu.Unlock()
+
+	entries, err := p.read(ctx, tool, args, extract)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer close(done)
+	l.fetching = nil
+	switch {
+	case err == nil:
+		p.store(key, l, entries, listTTL)
+	case ctx.Err() == nil:
+		p.store(key, l, nil, failureTTL)
+	case l.expires.IsZero():
+		// The caller gave up; the next request fetches again
+		p.remove(key, l)
+	}
+	return entries
+}
+
+// read fetches a reference list without caching it.
+func (p *Provider) read(ctx context.Context, tool string, args map[string]any, extract func(map[string]any) []entry) ([]entry, error) {
+	value, err := p.call(ctx, tool, args)
+	if err != nil {
+		slog.WarnContext(ctx, "Reference list for completions unavailable", "tool", tool, "error", err)
+		return nil, err
+	}
+	entries := extract(value)
+	slices.SortFunc(entries, func(a, b entry) int {
+		if len(a.value) != len(b.value) {
+			return len(a.value) - len(b.value)
+		}
+		return strings.Compare(a.value, b.value)
+	})
+	return entries, nil
+}
+
+// store caches entries as the content of l. When the cache would exceed
+// maxEntries, expired lists are dropped first and then the lists expiring
+// soonest; a list too long to cache at all is not kept. Callers hold p.mu.
+func (p *Provider) store(key string, l *list, entries []entry, ttl time.Duration) {
+	now := time.Now()
+	p.remove(key, l)
+	l.entries, l.expires = entries, now.Add(ttl)
+	if l.cost() > maxEntries {
+		return
+	}
+	if p.size+l.cost() > maxEntries {
+		p.sweep(now)
+	}
+	if p.size+l.cost() > maxEntries {
+		keys := slices.Collect(maps.Keys(p.lists))
+		slices.SortFunc(keys, func(a, b string) int { return p.lists[a].expires.Compare(p.lists[b].expires) })
+		for _, k := range keys {
+			if p.size+l.cost() <= maxEntries {
+				break
+			}
+			if p.lists[k].fetching == nil {
+				p.remove(k, p.lists[k])
+			}
+		}
+	}
+	p.lists[key] = l
+	p.size += l.cost()
+}
+
+// remove drops l from the cache if it is cached under key. Callers hold p.mu.
+func (p *Provider) remove(key string, l *list) {
+	if p.lists[key] == l {
+		delete(p.lists, key)
+		p.size -= l.cost()
+	}
+}
+
+// sweep drops expired lists. Lists being fetched are kept. Callers hold
+// p.mu.
+func (p *Provider) sweep(now time.Time) {
+	for key, l := range p.lists {
+		if l.fetching == nil && !now.Before(l.expires) {
+			p.remove(key, l)
+		}
+	}
+}
+
+// call runs a tool and returns its structured result.
+func (p *Provider) call(ctx context.Context, tool string, args map[string]any) (map[string]any, error) {
+	handler, ok := p.tools[tool]
+	if !ok || !p.usable(ctx, tool) {
+		return nil, fmt.Errorf("%s is not available", tool)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+	request.Params.Arguments = args
+	res, err := handler(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return result.Value(res)
+}
+
+// objects extracts the entries of a list of objects, identified by the
+// field valueKey and described by the field labelKey.
+func objects(valueKey, labelKey string) func(map[string]any) []entry {
+	return func(value map[string]any) []entry {
+		items, _ := value[result.ItemsKey].([]any)
+		entries := make([]entry, 0, len(items))
+		for _, v := range items {
+			item, _ := v.(map[string]any)
+			s, _ := item[valueKey].(string)
+			if s == "" {
+				continue
+			}
+			label, _ := item[labelKey].(string)
+			entries = append(entries, entry{value: s, key: strings.ToUpper(s), label: strings.ToLower(label)})
+		}
+		return entries
+	}
+}
+
+// names extracts the entries of a list of strings.
+func names(value map[string]any) []entry {
+	items, _ := value[result.ItemsKey].([]any)
+	entries := make([]entry, 0, len(items))
+	for _, v := range items {
+		if s, ok := v.(string); ok && s != "" {
+			entries = append(entries, entry{value: s, key: strings.ToUpper(s)})
+		}
+	}
+	return entries
+}
+
+// match returns the values of entries starting with value, followed by
+// those whose description contains it, ignoring case.
+func match(entries []entry, value string) []string {
+	upper, lower := strings.ToUpper(value), strings.ToLower(value)
+	var prefixed, described []string
+	for _, e := range entries {
+		switch {
+		case strings.HasPrefix(e.key, upper):
+			prefixed = append(prefixed, e.value)
+		case lower != "" && strings.Contains(e.label, lower):
+			described = append(described, e.value)
+		}
+	}
+	return append(prefixed, described...)
+}
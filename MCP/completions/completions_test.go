@@ -0,0 +1,208 @@
+package completions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeTools serves get_stock_symbol from symbols by exchange and records
+// every call.
+type fakeTools struct {
+	mu      sync.Mutex
+	calls   []string
+	symbols map[string][]string
+	failFor string // API key whose calls fail
+}
+
+func (f *fakeTools) handlers() map[string]server.ToolHandlerFunc {
+	return map[string]server.ToolHandlerFunc{
+		"get_stock_symbol": func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			exchange, _ := request.GetArguments()["exchange"].(string)
+			cfg, _ := config.FromContext(ctx)
+			f.mu.Lock()
+			f.calls = append(f.calls, exchange)
+			f.mu.Unlock()
+			if cfg != nil && cfg.APIKey == f.failFor {
+				return mcp.NewToolResultError("upstream unavailable"), nil
+			}
+			var items []any
+			for _, s := range f.symbols[exchange] {
+				items = append(items, map[string]any{"symbol": s, "description": s})
+			}
+			return &mcp.CallToolResult{StructuredContent: map[string]any{result.ItemsKey: items}}, nil
+		},
+	}
+}
+
+func (f *fakeTools) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func complete(p *Provider, ctx context.Context, value string) []string {
+	return p.complete(ctx, mcp.CompleteArgument{Name: "symbol", Value: value}).Values
+}
+
+func withKey(key string) context.Context {
+	return config.NewContext(context.Background(), &config.APIConfig{APIKey: key})
+}
+
+func allowed(_ context.Context, tool string) bool { return tool == "get_stock_symbol" }
+
+func TestSymbolSuffix(t *testing.T) {
+	tools := &fakeTools{symbols: map[string][]string{"US": {"AAPL"}, "DE": {"BMW.DE"}}}
+	p := New(tools.handlers(), allowed)
+	ctx := withKey("a")
+
+	if got := complete(p, ctx, "BMW.DE"); !slices.Equal(got, []string{"BMW.DE"}) {
+		t.Errorf("BMW.DE completes to %q", got)
+	}
+	// Suffixes that are not exchange codes, such as share classes, are not
+	// looked up as exchanges
+	for i := range 50 {
+		complete(p, ctx, fmt.Sprintf("BRK.X%d", i))
+	}
+	if got := tools.calls; !slices.Equal(got, []string{"US", "DE"}) {
+		t.Errorf("fetched %q, want US and DE only", got)
+	}
+}
+
+func TestFailuresPerKey(t *testing.T) {
+	tools := &fakeTools{symbols: map[string][]string{"US": {"AAPL"}}, failFor: "broken"}
+	p := New(tools.handlers(), allowed)
+
+	if got := complete(p, withKey("broken"), "AA"); len(got) != 0 {
+		t.Errorf("failing key completes to %q", got)
+	}
+	if got := complete(p, withKey("working"), "AA"); !slices.Equal(got, []string{"AAPL"}) {
+		t.Errorf("after another key failed, completes to %q", got)
+	}
+	// The failure is remembered for the failing key only
+	complete(p, withKey("broken"), "AA")
+	if n := tools.callCount(); n != 2 {
+		t.Errorf("fetched %d times, want 2", n)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	tools := &fakeTools{symbols: map[string][]string{"US": {"AAPL"}}}
+	p := New(tools.handlers(), allowed)
+	for i := range 3 {
+		complete(p, withKey(fmt.Sprint(i)), "AA")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.lists) != 3 || p.size != 6 {
+		t.Fatalf("%d lists of cost %d cached, want 3 of 6", len(p.lists), p.size)
+	}
+	pending := &list{fetching: make(chan struct{})}
+	p.lists["pending"] = pending
+	p.size += pending.cost()
+	p.sweep(time.Now().Add(listTTL + time.Second))
+	if len(p.lists) != 1 || p.lists["pending"] != pending || p.size != 1 {
+		t.Errorf("after sweep %d lists are left, want only the one being fetched", len(p.lists))
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	p := New(nil, allowed)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries := make([]entry, maxEntries/4)
+	for i := range 10 {
+		p.store(fmt.Sprint(i), &list{}, entries, listTTL+time.Duration(i)*time.Second)
+	}
+	// The lists expiring soonest make room for new ones
+	if p.size > maxEntries || len(p.lists) != 3 {
+		t.Errorf("%d lists of cost %d cached, want 3 within %d", len(p.lists), p.size, maxEntries)
+	}
+	for _, key := range []string{"7", "8", "9"} {
+		if _, ok := p.lists[key]; !ok {
+			t.Errorf("list %s evicted", key)
+		}
+	}
+
+	// A list too long to cache is not kept, and evicts nothing
+	p.store("huge", &list{}, make([]entry, maxEntries), listTTL)
+	if _, ok := p.lists["huge"]; ok || len(p.lists) != 3 {
+		t.Errorf("after a list over the limit, %d lists are cached", len(p.lists))
+	}
+}
+
+func TestSharedFetch(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	tools := &fakeTools{symbols: map[string][]string{"US": {"AAPL"}}}
+	handlers := tools.handlers()
+	fetch := handlers["get_stock_symbol"]
+	handlers["get_stock_symbol"] = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		<-release
+		return fetch(ctx, request)
+	}
+	p := New(handlers, allowed)
+
+	var wg sync.WaitGroup
+	results := make([][]string, 3)
+	for i := range results {
+		wg.Go(func() { results[i] = complete(p, withKey("a"), "AA") })
+	}
+	<-started
+
+	// A waiter whose request is cancelled returns without waiting for the
+	// fetch, and without the list lock blocking it
+	ctx, cancel := context.WithCancel(withKey("a"))
+	cancel()
+	waited := make(chan []string)
+	go func() { waited <- complete(p, ctx, "AA") }()
+	select {
+	case got := <-waited:
+		if len(got) != 0 {
+			t.Errorf("cancelled waiter completes to %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled waiter blocked by the fetch in flight")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, got := range results {
+		if !slices.Equal(got, []string{"AAPL"}) {
+			t.Errorf("caller %d completes to %q", i, got)
+		}
+	}
+	if n := tools.callCount(); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+}
+
+func TestCancelledFetch(t *testing.T) {
+	tools := &fakeTools{symbols: map[string][]string{"US": {"AAPL"}}}
+	handlers := tools.handlers()
+	fetch := handlers["get_stock_symbol"]
+	handlers["get_stock_symbol"] = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return fetch(ctx, request)
+	}
+	p := New(handlers, allowed)
+
+	// A fetch abandoned by its caller is not cached as a failure
+	ctx, cancel := context.WithCancel(withKey("a"))
+	cancel()
+	complete(p, ctx, "AA")
+	if got := complete(p, withKey("a"), "AA"); !slices.Equal(got, []string{"AAPL"}) {
+		t.Errorf("after a cancelled fetch, completes to %q", got)
+	}
+}
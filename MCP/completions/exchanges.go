@@ -0,0 +1,19 @@
+package completions
+
+// stockExchanges are the exchange codes Finnhub uses as symbol suffixes,
+// e.g. BMW.DE, from the list linked in get_stock_symbol's description. Only
+// these suffixes fetch an exchange's symbol list.
+var stockExchanges = map[string]bool{
+	"AS": true, "AT": true, "AX": true, "BA": true, "BC": true, "BD": true,
+	"BE": true, "BH": true, "BK": true, "BO": true, "BR": true, "CA": true,
+	"CN": true, "CO": true, "CR": true, "CS": true, "DB": true, "DE": true,
+	"DU": true, "F": true, "HA": true, "HE": true, "HK": true, "HM": true,
+	"IC": true, "IR": true, "IS": true, "JK": true, "JO": true, "KL": true,
+	"KQ": true, "KS": true, "KW": true, "L": true, "LS": true, "MC": true,
+	"ME": true, "MI": true, "MU": true, "MX": true, "NE": true, "NL": true,
+	"NS": true, "NZ": true, "OL": true, "PA": true, "PM": true, "PR": true,
+	"QA": true, "RG": true, "SA": true, "SG": true, "SI": true, "SN": true,
+	"SR": true, "SS": true, "ST": true, "SW": true, "SZ": true, "T": true,
+	"TA": true, "TG": true, "TL": true, "TO": true, "TW": true, "TWO": true,
+	"V": true, "VI": true, "VN": true, "VS": true, "WA": true,
+}
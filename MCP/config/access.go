@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// AccessConfig controls who may use the /mcp endpoint in HTTP mode. With
+// neither static tokens nor a JWKS configured the endpoint is open.
+type AccessConfig struct {
+	// Static bearer tokens, e.g. for service accounts
+	Tokens []string
+
+	// OAuth 2.1 resource server settings. Access tokens are JWTs signed by a
+	// key from JWKSURL and must carry Issuer and Audience.
+	JWKSURL              string
+	Issuer               string
+	Audience             string
+	RequiredScopes       []string
+	Resource             string   // Canonical URL of this server's /mcp endpoint
+	AuthorizationServers []string // Advertised in the protected resource metadata
+
+	// CA bundle for verifying client certificates in HTTPS mode
+	ClientCAFile string
+}
+
+func LoadAccessConfig() (*AccessConfig, error) {
+	c := &AccessConfig{
+		Tokens:               listEnv("MCP_AUTH_TOKENS"),
+		JWKSURL:              os.Getenv("OAUTH_JWKS_URL"),
+		Issuer:               os.Getenv("OAUTH_ISSUER"),
+		Audience:             os.Getenv("OAUTH_AUDIENCE"),
+		RequiredScopes:       listEnv("OAUTH_REQUIRED_SCOPES"),
+		Resource:             os.Getenv("OAUTH_RESOURCE"),
+		AuthorizationServers: listEnv("OAUTH_AUTHORIZATION_SERVERS"),
+		ClientCAFile:         os.Getenv("CLIENT_CA_FILE"),
+	}
+	if c.JWKSURL == "" {
+		return c, nil
+	}
+
+	// Tokens must be issued for this server, so the audience is mandatory;
+	// it defaults to the resource URL as in RFC 8707.
+	if c.Audience == "" {
+		c.Audience = c.Resource
+	}
+	if c.Issuer == "" || c.Audience == "" {
+		return nil, fmt.Errorf("OAUTH_JWKS_URL requires OAUTH_ISSUER and OAUTH_AUDIENCE (or OAUTH_RESOURCE)")
+	}
+	if len(c.AuthorizationServers) == 0 {
+		c.AuthorizationServers = []string{c.Issuer}
+	}
+	return c, nil
+}
+
+// OAuth reports whether access tokens are validated against a JWKS.
+func (c *AccessConfig) OAuth() bool {
+	return c.JWKSURL != ""
+}
+
+// Enabled reports whether /mcp requires authentication.
+func (c *AccessConfig) Enabled() bool {
+	return len(c.Tokens) > 0 || c.OAuth()
+}
@@ -1,8 +1,24 @@
 package config
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
 	"os"
+	pathpkg "path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/internal/plan"
 )
 
 type APIConfig struct {
@@ -10,9 +26,145 @@ type APIConfig struct {
 	BearerToken string // For OAuth2/Bearer authentication
 	APIKey      string // For API key authentication
 	BasicAuth   string // For basic authentication
+	APIKeyIn    string // Where the API key is sent: "header", "query" or "both"
 	Port        string // For server port configuration
+
+	// Outbound HTTP settings shared by every tool
+	RequestTimeout time.Duration // Deadline for a single upstream attempt
+	CallTimeout    time.Duration // Deadline for a whole tool call, including queueing and retries
+	ProxyURL       string        // HTTP proxy for upstream requests; falls back to HTTP(S)_PROXY
+	MaxIdleConns   int           // Idle keep-alive connections kept per upstream host
+
+	// Client-side quotas, tracked per API key across all sessions
+	RateLimitPerSecond int // Calls per second; 0 disables the limit
+	RateLimitPerMinute int // Calls per minute; 0 disables the limit
+
+	// Retry policy for transient upstream failures
+	RetryMaxAttempts int           // Total attempts per call, including the first
+	RetryBaseDelay   time.Duration // Backoff before the second attempt; doubles each time
+	RetryMaxDelay    time.Duration // Upper bound for a single backoff
+
+	// HTTP mode sessions
+	SessionIdleTimeout time.Duration // Sessions unused for this long are evicted
+
+	// Base URLs clients may select with the API_BASE_URL header
+	AllowedBaseURLs []string
+
+	// Tools exposed by the server, and the narrower choice a session made
+	// with headers at initialize (nil when it made none)
+	Tools        ToolSelection
+	SessionTools *ToolSelection
+
+	// Subscription of the API key, and whether tools it does not include
+	// are hidden or only flagged in their descriptions
+	Plan     plan.Plan
+	PlanMode string
+
+	// Resource subscriptions
+	QuoteFeed         string        // Where quote updates come from: "poll" or "websocket"
+	QuotePollInterval time.Duration // Polling cadence, and the minimum gap between updates of a quote
+	QuoteWebSocketURL string        // Finnhub's trades websocket
+	MaxSubscriptions  int           // Per session; 0 disables subscriptions
+
+	// Response cache
+	CacheBackend    string                   // "memory", "disk" or "off"
+	CacheDir        string                   // Directory of the disk backend
+	CacheMaxEntries int                      // Capacity of the memory backend
+	CacheTTLs       map[string]time.Duration // By tool name; tools not listed are not cached
+
+	// Logging
+	LogLevel  slog.Level // Least severe level written to stderr
+	LogFormat string     // "text" or "json"
 }
 
+// Quote feeds accepted by QuoteFeed.
+const (
+	QuoteFeedPoll      = "poll"
+	QuoteFeedWebSocket = "websocket"
+)
+
+// Cache backends accepted by CacheBackend.
+const (
+	CacheMemory = "memory"
+	CacheDisk   = "disk"
+	CacheOff    = "off"
+)
+
+// Log formats accepted by LogFormat.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// DefaultCacheTTLs are how long responses are cached unless CACHE_TTLS says
+// otherwise: a day for reference lists and profiles, which rarely change,
+// and a few seconds for quotes, which clients tend to ask for repeatedly.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"get_stock_symbol":        24 * time.Hour,
+	"get_crypto_symbol":       24 * time.Hour,
+	"get_forex_symbol":        24 * time.Hour,
+	"get_crypto_exchange":     24 * time.Hour,
+	"get_forex_exchange":      24 * time.Hour,
+	"get_country":             24 * time.Hour,
+	"get_economic_code":       24 * time.Hour,
+	"get_stock_profile":       24 * time.Hour,
+	"get_stock_profile2":      24 * time.Hour,
+	"get_etf_profile":         24 * time.Hour,
+	"get_mutual-fund_profile": 24 * time.Hour,
+	"get_bond_profile":        24 * time.Hour,
+	"get_crypto_profile":      24 * time.Hour,
+	"get_quote":               5 * time.Second,
+}
+
+// Plan modes accepted by PlanMode.
+const (
+	PlanModeHide     = "hide"
+	PlanModeAnnotate = "annotate"
+)
+
+// ToolSelection chooses which tools are exposed, by toolset and by name.
+type ToolSelection struct {
+	Toolsets []string // Enabled toolsets, or "all"; nil enables all unless Tools is set
+	Tools    []string // Tools enabled in addition to the toolsets
+	Exclude  []string // Tools disabled even if their toolset is enabled
+}
+
+// Enabled reports whether the tool with the given name and toolset is
+// selected.
+func (s *ToolSelection) Enabled(name, toolset string) bool {
+	if slices.Contains(s.Exclude, name) {
+		return false
+	}
+	if slices.Contains(s.Tools, name) {
+		return true
+	}
+	if s.Toolsets == nil {
+		return len(s.Tools) == 0
+	}
+	return slices.Contains(s.Toolsets, "all") || slices.Contains(s.Toolsets, toolset)
+}
+
+// ToolEnabled reports whether a tool is available: the server has to expose
+// it, and a session that selected tools can only narrow that further.
+func (c *APIConfig) ToolEnabled(name, toolset string) bool {
+	if !c.Tools.Enabled(name, toolset) {
+		return false
+	}
+	return c.SessionTools == nil || c.SessionTools.Enabled(name, toolset)
+}
+
+// DefaultBaseURL is the Finnhub API, which header-supplied base URLs may
+// always point at.
+const DefaultBaseURL = "https://finnhub.io/api/v1"
+
+// API key locations accepted by APIKeyIn. Finnhub reads the key from either
+// the X-Finnhub-Token header or the token query parameter.
+const (
+	APIKeyInHeader = "header"
+	APIKeyInQuery  = "query"
+	APIKeyInBoth   = "both"
+)
+
 func LoadAPIConfig() (*APIConfig, error) {
 	// Check port environment variable (both uppercase and lowercase)
 	port := os.Getenv("PORT")
@@ -36,13 +188,395 @@ func LoadAPIConfig() (*APIConfig, error) {
 	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
+	apiKeyIn := os.Getenv("API_KEY_IN")
+	switch apiKeyIn {
+	case "":
+		apiKeyIn = APIKeyInHeader
+	case APIKeyInHeader, APIKeyInQuery, APIKeyInBoth:
+	default:
+		return nil, fmt.Errorf("invalid API_KEY_IN %q: must be %q, %q or %q", apiKeyIn, APIKeyInHeader, APIKeyInQuery, APIKeyInBoth)
+	}
+
+	requestTimeout, err := durationEnv("REQUEST_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	callTimeout, err := durationEnv("CALL_TIMEOUT", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	maxIdleConns, err := intEnv("MAX_IDLE_CONNS", 100)
+	if err != nil {
+		return nil, err
+	}
+	// Defaults match Finnhub's free tier
+	ratePerSecond, err := intEnv("RATE_LIMIT_PER_SECOND", 30)
+	if err != nil {
+		return nil, err
+	}
+	ratePerMinute, err := intEnv("RATE_LIMIT_PER_MINUTE", 60)
+	if err != nil {
+		return nil, err
+	}
+	retryMaxAttempts, err := intEnv("RETRY_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+	retryBaseDelay, err := durationEnv("RETRY_BASE_DELAY", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	retryMaxDelay, err := durationEnv("RETRY_MAX_DELAY", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	sessionIdleTimeout, err := durationEnv("SESSION_IDLE_TIMEOUT", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPlan, err := plan.Parse(os.Getenv("PLAN"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLAN: %w", err)
+	}
+	planMode := os.Getenv("PLAN_MODE")
+	switch planMode {
+	case "":
+		planMode = PlanModeHide
+	case PlanModeHide, PlanModeAnnotate:
+	default:
+		return nil, fmt.Errorf("invalid PLAN_MODE %q: must be %q or %q", planMode, PlanModeHide, PlanModeAnnotate)
+	}
+
+	quoteFeed := os.Getenv("QUOTE_FEED")
+	switch quoteFeed {
+	case "":
+		quoteFeed = QuoteFeedPoll
+	case QuoteFeedPoll, QuoteFeedWebSocket:
+	default:
+		return nil, fmt.Errorf("invalid QUOTE_FEED %q: must be %q or %q", quoteFeed, QuoteFeedPoll, QuoteFeedWebSocket)
+	}
+	quotePollInterval, err := durationEnv("QUOTE_POLL_INTERVAL", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if quotePollInterval <= 0 {
+		return nil, fmt.Errorf("invalid QUOTE_POLL_INTERVAL %s: must be positive", quotePollInterval)
+	}
+	quoteWebSocketURL := os.Getenv("QUOTE_WEBSOCKET_URL")
+	if quoteWebSocketURL == "" {
+		quoteWebSocketURL = "wss://ws.finnhub.io"
+	}
+	maxSubscriptions, err := intEnv("MAX_SUBSCRIPTIONS", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheBackend := os.Getenv("CACHE_BACKEND")
+	switch cacheBackend {
+	case "":
+		cacheBackend = CacheMemory
+	case CacheMemory, CacheDisk, CacheOff:
+	default:
+		return nil, fmt.Errorf("invalid CACHE_BACKEND %q: must be %q, %q or %q", cacheBackend, CacheMemory, CacheDisk, CacheOff)
+	}
+	cacheDir := os.Getenv("CACHE_DIR")
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		cacheDir = filepath.Join(dir, "finnhub-mcp-server")
+	}
+	cacheMaxEntries, err := intEnv("CACHE_MAX_ENTRIES", 1000)
+	if err != nil {
+		return nil, err
+	}
+	if cacheMaxEntries <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_MAX_ENTRIES %d: must be positive", cacheMaxEntries)
+	}
+	cacheTTLs := maps.Clone(DefaultCacheTTLs)
+	for _, entry := range listEnv("CACHE_TTLS") {
+		tool, v, ok := strings.Cut(entry, "=")
+		tool, v = strings.TrimSpace(tool), strings.TrimSpace(v)
+		ttl, err := parseDuration(v)
+		if !ok || tool == "" || err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid CACHE_TTLS entry %q: want <tool>=<duration>", entry)
+		}
+		cacheTTLs[tool] = ttl
+	}
+
+	var logLevel slog.Level
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := logLevel.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: must be debug, info, warn or error", v)
+		}
+	}
+	logFormat := strings.ToLower(os.Getenv("LOG_FORMAT"))
+	switch logFormat {
+	case "":
+		logFormat = LogFormatText
+	case LogFormatText, LogFormatJSON:
+	default:
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q: must be %q or %q", logFormat, LogFormatText, LogFormatJSON)
+	}
+
+	// The operator's own API_BASE_URL is trusted; anything else a client
+	// sends has to be listed so the server cannot be used as an open proxy.
+	allowedBaseURLs := []string{DefaultBaseURL}
+	if baseURL != "" {
+		allowedBaseURLs = append(allowedBaseURLs, baseURL)
+	}
+	allowedBaseURLs = append(allowedBaseURLs, listEnv("ALLOWED_BASE_URLS")...)
+	for _, allowed := range allowedBaseURLs {
+		if allowed == "*" {
+			continue
+		}
+		if u, err := url.Parse(allowed); err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid ALLOWED_BASE_URLS entry %q", allowed)
+		}
+	}
+
 	return &APIConfig{
-		BaseURL:     baseURL,
-		BearerToken: os.Getenv("BEARER_TOKEN"),
-		APIKey:      os.Getenv("API_KEY"),
-		BasicAuth:   os.Getenv("BASIC_AUTH"),
-		Port:        port,
+		BaseURL:        baseURL,
+		BearerToken:    os.Getenv("BEARER_TOKEN"),
+		APIKey:         os.Getenv("API_KEY"),
+		BasicAuth:      os.Getenv("BASIC_AUTH"),
+		APIKeyIn:       apiKeyIn,
+		Port:           port,
+		RequestTimeout: requestTimeout,
+		CallTimeout:    callTimeout,
+		ProxyURL:       os.Getenv("PROXY_URL"),
+		MaxIdleConns:   maxIdleConns,
+
+		RateLimitPerSecond: ratePerSecond,
+		RateLimitPerMinute: ratePerMinute,
+
+		RetryMaxAttempts: retryMaxAttempts,
+		RetryBaseDelay:   retryBaseDelay,
+		RetryMaxDelay:    retryMaxDelay,
+
+		SessionIdleTimeout: sessionIdleTimeout,
+
+		AllowedBaseURLs: allowedBaseURLs,
+
+		Tools: ToolSelection{
+			Toolsets: listEnv("TOOLSETS"),
+			Tools:    listEnv("TOOLS"),
+			Exclude:  listEnv("EXCLUDE_TOOLS"),
+		},
+
+		Plan:     keyPlan,
+		PlanMode: planMode,
+
+		QuoteFeed:         quoteFeed,
+		QuotePollInterval: quotePollInterval,
+		QuoteWebSocketURL: quoteWebSocketURL,
+		MaxSubscriptions:  maxSubscriptions,
+
+		CacheBackend:    cacheBackend,
+		CacheDir:        cacheDir,
+		CacheMaxEntries: cacheMaxEntries,
+		CacheTTLs:       cacheTTLs,
+
+		LogLevel:  logLevel,
+		LogFormat: logFormat,
 	}, nil
 }
 
+// CacheTTL returns how long responses of tool are cached, 0 for not at all.
+func (c *APIConfig) CacheTTL(tool string) time.Duration {
+	if c.CacheBackend == CacheOff {
+		return 0
+	}
+	return c.CacheTTLs[tool]
+}
+
+// ForRequest returns a copy of c with the per-request API configuration taken
+// from HTTP headers. The API_BASE_URL header is required and must be one of
+// the allowed base URLs. Server-wide settings such as timeouts are kept from c,
+// while RATE_LIMIT_PER_SECOND and RATE_LIMIT_PER_MINUTE headers let a client
+// describe a quota of the key it sends that is smaller than the server's.
+func (c *APIConfig) ForRequest(h http.Header) (*APIConfig, error) {
+	reqCfg := *c
+	reqCfg.BaseURL = h.Get("API_BASE_URL")
+	reqCfg.BearerToken = h.Get("BEARER_TOKEN")
+	reqCfg.APIKey = h.Get("API_KEY")
+	reqCfg.BasicAuth = h.Get("BASIC_AUTH")
+	// Accept Finnhub's own header name as an alias for API_KEY
+	if reqCfg.APIKey == "" {
+		reqCfg.APIKey = h.Get("X-Finnhub-Token")
+	}
 
+	if reqCfg.BaseURL == "" {
+		return nil, errors.New("missing API_BASE_URL header")
+	}
+	if !reqCfg.BaseURLAllowed(reqCfg.BaseURL) {
+		return nil, fmt.Errorf("API_BASE_URL %q is not allowed; add it to ALLOWED_BASE_URLS on the server", redactUserinfo(reqCfg.BaseURL))
+	}
+
+	if sel := (ToolSelection{
+		Toolsets: listHeader(h, "TOOLSETS"),
+		Tools:    listHeader(h, "TOOLS"),
+		Exclude:  listHeader(h, "EXCLUDE_TOOLS"),
+	}); sel.Toolsets != nil || sel.Tools != nil || sel.Exclude != nil {
+		reqCfg.SessionTools = &sel
+	}
+
+	// The plan belongs to the key, so a session sending its own key may say
+	// which plan that key is on
+	if v := h.Get("PLAN"); v != "" {
+		p, err := plan.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PLAN header: %w", err)
+		}
+		reqCfg.Plan = p
+	}
+
+	var err error
+	if reqCfg.RateLimitPerSecond, err = limitHeader(h, "RATE_LIMIT_PER_SECOND", c.RateLimitPerSecond); err != nil {
+		return nil, err
+	}
+	if reqCfg.RateLimitPerMinute, err = limitHeader(h, "RATE_LIMIT_PER_MINUTE", c.RateLimitPerMinute); err != nil {
+		return nil, err
+	}
+	return &reqCfg, nil
+}
+
+// CredentialKey returns a hash of c's credentials, for keeping state per API
+// key, such as rate limits and cached responses, without holding the secrets.
+func (c *APIConfig) CredentialKey() string {
+	sum := sha256.Sum256([]byte(c.APIKey + "\x00" + c.BearerToken + "\x00" + c.BasicAuth))
+	return hex.EncodeToString(sum[:])
+}
+
+// BaseURLAllowed reports whether raw points at one of the allowed base URLs:
+// same scheme and host, and a path at or below the allowed path. An entry of
+// "*" allows any http(s) URL.
+func (c *APIConfig) BaseURLAllowed(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	// Clean the path so "/api/v1/../../admin" cannot escape an allowed prefix
+	path := strings.TrimRight(pathpkg.Clean("/"+u.Path), "/")
+	return slices.ContainsFunc(c.AllowedBaseURLs, func(allowed string) bool {
+		if allowed == "*" {
+			return true
+		}
+		a, err := url.Parse(allowed)
+		if err != nil || !strings.EqualFold(a.Scheme, u.Scheme) || !strings.EqualFold(a.Host, u.Host) {
+			return false
+		}
+		prefix := strings.TrimRight(a.Path, "/")
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	})
+}
+
+// redactUserinfo drops credentials embedded in a URL before it is echoed back
+// in an error.
+func redactUserinfo(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	u.User = nil
+	return u.String()
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying cfg, the API configuration of the
+// session a request belongs to.
+func NewContext(ctx context.Context, cfg *APIConfig) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext returns the session configuration stored by NewContext.
+func FromContext(ctx context.Context) (*APIConfig, bool) {
+	cfg, ok := ctx.Value(contextKey{}).(*APIConfig)
+	return cfg, ok && cfg != nil
+}
+
+// ForContext returns the session configuration carried by ctx, or fallback
+// when there is none (STDIO mode).
+func ForContext(ctx context.Context, fallback *APIConfig) *APIConfig {
+	if cfg, ok := FromContext(ctx); ok {
+		return cfg
+	}
+	return fallback
+}
+
+// durationEnv reads a duration such as "30s" or a plain number of seconds.
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := parseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, v, err)
+	}
+	return d, nil
+}
+
+// parseDuration parses a duration such as "30s" or a plain number of seconds.
+func parseDuration(v string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// listEnv reads a comma-separated list, ignoring blank entries.
+func listEnv(name string) []string {
+	return SplitList(os.Getenv(name))
+}
+
+func listHeader(h http.Header, name string) []string {
+	return SplitList(h.Get(name))
+}
+
+// SplitList splits a comma-separated list, ignoring blank entries. It
+// returns nil when there are no entries.
+func SplitList(v string) []string {
+	var list []string
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+func intEnv(name string, def int) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, v, err)
+	}
+	return n, nil
+}
+
+// limitHeader reads a rate limit header, which may lower the server's limit
+// but not raise or disable it. A limit of 0 means the server sets none.
+func limitHeader(h http.Header, name string, limit int) (int, error) {
+	v := h.Get(name)
+	if v == "" {
+		return limit, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s header %q: %w", name, v, err)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("invalid %s header %q: must be at least 1", name, v)
+	}
+	if limit > 0 && n > limit {
+		return 0, fmt.Errorf("invalid %s header %q: must not exceed the server's limit of %d", name, v, limit)
+	}
+	return n, nil
+}
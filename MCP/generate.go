@@ -0,0 +1,5 @@
+package main
+
+// The tool definitions in tools/default and the registry are generated from
+// Finnhub's swagger.json. Re-run after updating the spec.
+//go:generate go run ./cmd/gentools -spec ../swagger.json -out tools/default -registry registry.go
@@ -0,0 +1,230 @@
+// Package access guards the /mcp endpoint in HTTP mode. Callers authenticate
+// with a bearer token that is either one of the configured static tokens or
+// an OAuth 2.1 access token (a JWT) issued for this server, following the MCP
+// authorization spec for resource servers. Unauthenticated requests get a 401
+// pointing at the protected resource metadata (RFC 9728).
+package access
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MetadataPath is where the protected resource metadata is served.
+const MetadataPath = "/.well-known/oauth-protected-resource"
+
+// Guard authenticates requests according to an AccessConfig.
+type Guard struct {
+	cfg    *config.AccessConfig
+	keys   *jwks
+	parser *jwt.Parser
+}
+
+func New(cfg *config.AccessConfig) *Guard {
+	g := &Guard{cfg: cfg}
+	if cfg.OAuth() {
+		g.keys = newJWKS(cfg.JWKSURL)
+		g.parser = jwt.NewParser(
+			jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(30*time.Second),
+		)
+	}
+	return g
+}
+
+type principalKey struct{}
+
+// Principal returns the identity a request was authenticated as, or "" when
+// authentication is disabled. Sessions are bound to it so a session ID alone
+// cannot be used by someone else.
+func Principal(ctx context.Context) string {
+	p, _ := ctx.Value(principalKey{}).(string)
+	return p
+}
+
+// Middleware rejects requests without valid credentials.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := clientCertificate(r)
+		if g.cfg.Enabled() {
+			token, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				g.challenge(w, r, http.StatusUnauthorized, "", "")
+				return
+			}
+			var err error
+			if principal, err = g.authenticate(r.Context(), token); err != nil {
+				status, code := http.StatusUnauthorized, "invalid_token"
+				if errors.Is(err, errInsufficientScope) {
+					status, code = http.StatusForbidden, "insufficient_scope"
+				}
+				g.challenge(w, r, status, code, err.Error())
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+var errInsufficientScope = errors.New("token is missing a required scope")
+
+func (g *Guard) authenticate(ctx context.Context, token string) (string, error) {
+	for _, t := range g.cfg.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			sum := sha256.Sum256([]byte(t))
+			return "token:" + hex.EncodeToString(sum[:8]), nil
+		}
+	}
+	if !g.cfg.OAuth() {
+		return "", errors.New("unknown token")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := g.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return g.keys.key(ctx, kid)
+	})
+	if err != nil {
+		return "", err
+	}
+	if !hasScopes(claims, g.cfg.RequiredScopes) {
+		return "", errInsufficientScope
+	}
+	sub, _ := claims.GetSubject()
+	return "oauth:" + g.cfg.Issuer + "#" + sub, nil
+}
+
+// hasScopes checks the space-separated "scope" claim (RFC 9068), falling back
+// to the "scp" array some providers use.
+func hasScopes(claims jwt.MapClaims, required []string) bool {
+	var granted []string
+	if scope, ok := claims["scope"].(string); ok {
+		granted = strings.Fields(scope)
+	}
+	if scp, ok := claims["scp"].([]any); ok {
+		for _, s := range scp {
+			if str, ok := s.(string); ok {
+				granted = append(granted, str)
+			}
+		}
+	}
+	for _, s := range required {
+		if !slices.Contains(granted, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// challenge writes a 401 or 403 with a WWW-Authenticate header as described
+// in RFC 6750 and RFC 9728.
+func (g *Guard) challenge(w http.ResponseWriter, r *http.Request, status int, code, description string) {
+	params := []string{`realm="mcp"`}
+	if g.cfg.OAuth() {
+		params = append(params, fmt.Sprintf("resource_metadata=%q", g.metadataURL(r)))
+	}
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%q", code), fmt.Sprintf("error_description=%q", description))
+	}
+	if code == "insufficient_scope" {
+		params = append(params, fmt.Sprintf("scope=%q", strings.Join(g.cfg.RequiredScopes, " ")))
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer "+strings.Join(params, ", "))
+	http.Error(w, http.StatusText(status), status)
+}
+
+// MetadataHandler serves the OAuth 2.0 protected resource metadata that
+// tells MCP clients which authorization server issues tokens for /mcp.
+func (g *Guard) MetadataHandler(w http.ResponseWriter, r *http.Request) {
+	if !g.cfg.OAuth() {
+		http.NotFound(w, r)
+		return
+	}
+	metadata := map[string]any{
+		"resource":                 g.resource(r),
+		"authorization_servers":    g.cfg.AuthorizationServers,
+		"bearer_methods_supported": []string{"header"},
+	}
+	if len(g.cfg.RequiredScopes) > 0 {
+		metadata["scopes_supported"] = g.cfg.RequiredScopes
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(metadata)
+}
+
+func (g *Guard) resource(r *http.Request) string {
+	if g.cfg.Resource != "" {
+		return g.cfg.Resource
+	}
+	return origin(r) + "/mcp"
+}
+
+func (g *Guard) metadataURL(r *http.Request) string {
+	return origin(r) + MetadataPath
+}
+
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// clientCertificate identifies callers by their verified client certificate
+// when mTLS is enabled and no token authentication is configured.
+func clientCertificate(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return ""
+	}
+	return "cert:" + r.TLS.VerifiedChains[0][0].Subject.String()
+}
+
+// TLSConfig returns the server TLS configuration requiring client
+// certificates signed by the CA bundle in cfg.ClientCAFile, or nil when mTLS
+// is not configured.
+func TLSConfig(cfg *config.AccessConfig) (*tls.Config, error) {
+	if cfg.ClientCAFile == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read CLIENT_CA_FILE: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CLIENT_CA_FILE %s contains no certificates", cfg.ClientCAFile)
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,344 @@
+package access
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://auth.example"
+	testAudience = "https://mcp.example/mcp"
+)
+
+// jwksServer publishes the public halves of keys as a JWKS and counts how
+// often it is fetched.
+type jwksServer struct {
+	*httptest.Server
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, keys map[string]*ecdsa.PrivateKey) *jwksServer {
+	t.Helper()
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, k := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(k.X.Bytes()),
+			Y:   base64.RawURLEncoding.EncodeToString(k.Y.Bytes()),
+		})
+	}
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func sign(t *testing.T, key any, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthenticate(t *testing.T) {
+	key, other := newKey(t), newKey(t)
+	keys := newJWKSServer(t, map[string]*ecdsa.PrivateKey{"k1": key})
+	g := New(&config.AccessConfig{
+		Tokens:         []string{"static-token"},
+		JWKSURL:        keys.URL,
+		Issuer:         testIssuer,
+		Audience:       testAudience,
+		RequiredScopes: []string{"mcp"},
+	})
+
+	now := time.Now()
+	claims := func(edit func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   testIssuer,
+			"aud":   testAudience,
+			"sub":   "alice",
+			"exp":   now.Add(time.Hour).Unix(),
+			"iat":   now.Unix(),
+			"scope": "read mcp",
+		}
+		if edit != nil {
+			edit(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		principal string
+		wantErr   error // nil accepts any error when principal is ""
+	}{
+		{
+			name:      "static token",
+			token:     "static-token",
+			principal: "token:",
+		},
+		{
+			name:      "valid",
+			token:     sign(t, key, jwt.SigningMethodES256, "k1", claims(nil)),
+			principal: "oauth:" + testIssuer + "#alice",
+		},
+		{
+			name:      "audience list",
+			token:     sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["aud"] = []string{"other", testAudience} })),
+			principal: "oauth:" + testIssuer + "#alice",
+		},
+		{
+			name:      "scp claim",
+			token:     sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { delete(c, "scope"); c["scp"] = []string{"mcp"} })),
+			principal: "oauth:" + testIssuer + "#alice",
+		},
+		{
+			name:      "expired within leeway",
+			token:     sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() })),
+			principal: "oauth:" + testIssuer + "#alice",
+		},
+		{
+			name:    "expired",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() })),
+			wantErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:    "no expiry",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			wantErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "not yet valid",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() })),
+			wantErr: jwt.ErrTokenNotValidYet,
+		},
+		{
+			name:    "wrong audience",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["aud"] = "https://other.example/mcp" })),
+			wantErr: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "no audience",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { delete(c, "aud") })),
+			wantErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "wrong issuer",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example" })),
+			wantErr: jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "signed by another key",
+			token:   sign(t, other, jwt.SigningMethodES256, "k1", claims(nil)),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "unknown key",
+			token:   sign(t, other, jwt.SigningMethodES256, "k2", claims(nil)),
+			wantErr: jwt.ErrTokenUnverifiable,
+		},
+		{
+			name:    "HMAC",
+			token:   sign(t, []byte("static-token"), jwt.SigningMethodHS256, "k1", claims(nil)),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "none",
+			token:   sign(t, jwt.UnsafeAllowNoneSignatureType, jwt.SigningMethodNone, "k1", claims(nil)),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "missing scope",
+			token:   sign(t, key, jwt.SigningMethodES256, "k1", claims(func(c jwt.MapClaims) { c["scope"] = "read" })),
+			wantErr: errInsufficientScope,
+		},
+		{
+			name:    "malformed",
+			token:   "not.a.jwt",
+			wantErr: jwt.ErrTokenMalformed,
+		},
+		{
+			name:  "unknown static token",
+			token: "static-token2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := g.authenticate(context.Background(), tt.token)
+			if tt.principal != "" {
+				if err != nil {
+					t.Fatalf("authenticate: %v", err)
+				}
+				if !strings.HasPrefix(principal, tt.principal) {
+					t.Errorf("principal = %q, want %q", principal, tt.principal)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("authenticate accepted the token as %q", principal)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("authenticate error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticateStaticTokensOnly(t *testing.T) {
+	g := New(&config.AccessConfig{Tokens: []string{"a", "b"}})
+	pa, err := g.authenticate(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := g.authenticate(context.Background(), "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa == pb || !strings.HasPrefix(pa, "token:") || !strings.HasPrefix(pb, "token:") {
+		t.Errorf("principals %q and %q do not identify the tokens", pa, pb)
+	}
+	// A JWT is not tried without OAuth configured
+	if _, err := g.authenticate(context.Background(), "eyJhbGciOiJub25lIn0.e30."); err == nil {
+		t.Error("authenticate accepted an unknown token")
+	}
+}
+
+func TestJWKSKey(t *testing.T) {
+	key := newKey(t)
+	keys := newJWKSServer(t, map[string]*ecdsa.PrivateKey{"k1": key})
+	ctx := context.Background()
+
+	j := newJWKS(keys.URL)
+	for range 3 {
+		if _, err := j.key(ctx, "k1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := keys.fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times for cached key, want 1", n)
+	}
+
+	// Unknown keys refresh the set at most once per jwksMinRefreshInterval
+	for range 3 {
+		if _, err := j.key(ctx, "k2"); err == nil {
+			t.Fatal("key returned an unknown key")
+		}
+	}
+	if n := keys.fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times for unknown keys within the minimum interval, want 1", n)
+	}
+	j.fetchedAt = time.Now().Add(-jwksMinRefreshInterval)
+	if _, err := j.key(ctx, "k2"); err == nil {
+		t.Fatal("key returned an unknown key")
+	}
+	if n := keys.fetches.Load(); n != 2 {
+		t.Errorf("fetched %d times after the minimum interval, want 2", n)
+	}
+
+	// Stale keys are still used while the endpoint is down
+	keys.Close()
+	j.fetchedAt = time.Now().Add(-2 * jwksRefreshInterval)
+	if _, err := j.key(ctx, "k1"); err != nil {
+		t.Errorf("key while the endpoint is down: %v", err)
+	}
+	if _, err := j.key(ctx, "k3"); err == nil {
+		t.Error("key returned an unknown key while the endpoint is down")
+	}
+}
+
+func TestJWKSRefreshDoesNotBlockCachedKeys(t *testing.T) {
+	key := newKey(t)
+	release := make(chan struct{})
+	entered := make(chan struct{}, 16)
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		entered <- struct{}{}
+		<-release
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	j := newJWKS(srv.URL)
+	j.keys = map[string]crypto.PublicKey{"k1": &key.PublicKey}
+	j.fetchedAt = time.Now().Add(-2 * jwksMinRefreshInterval)
+
+	// Concurrent lookups of an unknown key share one fetch
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.key(context.Background(), "k2")
+		}()
+	}
+	<-entered
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := j.key(context.Background(), "k1")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("cached key: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cached key lookup blocked on the JWKS refresh")
+	}
+
+	// A waiter gives up with its own context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := j.key(ctx, "k3"); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled lookup error = %v, want context.Canceled", err)
+	}
+
+	release <- struct{}{}
+	wg.Wait()
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+}
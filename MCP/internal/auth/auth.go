@@ -0,0 +1,172 @@
+// Package auth attaches the credentials from an APIConfig to outbound Finnhub
+// requests and keeps those credentials out of anything that gets logged or
+// returned to MCP clients.
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/finnhub-api/mcp-server/config"
+)
+
+const (
+	// TokenHeader is the header Finnhub reads the API key from.
+	TokenHeader = "X-Finnhub-Token"
+	// TokenParam is the query parameter declared by the api_key security scheme.
+	TokenParam = "token"
+
+	redacted = "REDACTED"
+)
+
+// Transport is an http.RoundTripper that adds the configured credentials to
+// every request sent to the configured base URL. Credentials are only added
+// to a clone of the request, so the caller's request (and any *url.Error that
+// wraps it) never contains the secret.
+type Transport struct {
+	Config *config.APIConfig
+	Base   http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Config == nil || !sameHost(req.URL, t.Config.BaseURL) {
+		// Never forward credentials to a host we were redirected to.
+		return base.RoundTrip(req)
+	}
+
+	authed := req.Clone(req.Context())
+	Apply(authed, t.Config)
+
+	resp, err := base.RoundTrip(authed)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+	return resp, err
+}
+
+// Apply sets the credentials from cfg on req. API keys are sent as the
+// X-Finnhub-Token header, the token query parameter, or both, depending on
+// cfg.APIKeyIn. Bearer tokens take precedence over basic auth.
+func Apply(req *http.Request, cfg *config.APIConfig) {
+	if cfg.APIKey != "" {
+		in := cfg.APIKeyIn
+		if in == "" {
+			in = config.APIKeyInHeader
+		}
+		if in == config.APIKeyInHeader || in == config.APIKeyInBoth {
+			req.Header.Set(TokenHeader, cfg.APIKey)
+		}
+		if in == config.APIKeyInQuery || in == config.APIKeyInBoth {
+			param := TokenParam + "=" + url.QueryEscape(cfg.APIKey)
+			if q, ok := replaceQueryParam(req.URL.RawQuery, TokenParam, param); ok {
+				req.URL.RawQuery = q
+			} else if req.URL.RawQuery == "" {
+				req.URL.RawQuery = param
+			} else {
+				req.URL.RawQuery += "&" + param
+			}
+		}
+	}
+
+	switch {
+	case cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+cfg.BearerToken)
+	case cfg.BasicAuth != "":
+		req.Header.Set("Authorization", "Basic "+basicCredentials(cfg.BasicAuth))
+	}
+}
+
+// basicCredentials accepts either "user:password" or an already encoded
+// credential string.
+func basicCredentials(value string) string {
+	if strings.Contains(value, ":") {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	return value
+}
+
+func sameHost(u *url.URL, baseURL string) bool {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, base.Host)
+}
+
+// RedactURL masks the token query parameter and any userinfo password in raw.
+// Values that do not parse as URLs are returned unchanged.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	if q, ok := replaceQueryParam(u.RawQuery, TokenParam, TokenParam+"="+redacted); ok {
+		u.RawQuery = q
+	}
+	return u.String()
+}
+
+// replaceQueryParam replaces every name parameter of rawQuery with param and
+// reports whether there was one. The other parameters are kept as they were
+// encoded: url.Values.Encode would sort them and re-encode them, turning %20
+// into +.
+func replaceQueryParam(rawQuery, name, param string) (string, bool) {
+	pairs := strings.Split(rawQuery, "&")
+	found := false
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if key == name {
+			pairs[i] = param
+			found = true
+		}
+	}
+	return strings.Join(pairs, "&"), found
+}
+
+// secretHeaders are the request headers that carry credentials: Finnhub's,
+// the configuration headers of HTTP mode and the standard ones.
+var secretHeaders = []string{TokenHeader, "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "API_KEY", "BEARER_TOKEN", "BASIC_AUTH"}
+
+// RedactHeader returns a copy of h with the values of credential headers
+// masked.
+func RedactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for name, values := range h {
+		for _, secret := range secretHeaders {
+			if strings.EqualFold(name, secret) {
+				for i := range values {
+					values[i] = redacted
+				}
+			}
+		}
+	}
+	return h
+}
+
+var (
+	urlPattern        = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+	credentialPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-zA-Z0-9._~+/=-]+`)
+)
+
+// RedactText masks credentials in free text such as an error message: the
+// secrets of every URL it contains, as RedactURL does, and bearer and basic
+// authorization values.
+func RedactText(s string) string {
+	s = urlPattern.ReplaceAllStringFunc(s, RedactURL)
+	return credentialPattern.ReplaceAllString(s, "$1 "+redacted)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// Argument is the tool argument that skips the cache for one call.
+	Argument = "cache"
+	// Header is the HTTP header that skips the cache for the calls of a
+	// request.
+	Header = "Cache"
+	// Bypass is the value of Argument and Header that skips the cache.
+	Bypass = "bypass"
+)
+
+type bypassKey struct{}
+
+// WithBypass returns a context whose upstream requests skip the cache. The
+// response is fetched and stored as usual.
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Bypassed reports whether ctx skips the cache.
+func Bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
+
+// Middleware handles the cache argument of cached tools, which the tool
+// handlers do not know about, and the Cache header of HTTP requests. The
+// session's configuration carried by ctx takes precedence over base.
+func Middleware(base *config.APIConfig) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if strings.EqualFold(request.Header.Get(Header), Bypass) {
+				ctx = WithBypass(ctx)
+			}
+			args, ok := request.Params.Arguments.(map[string]any)
+			if !ok || config.ForContext(ctx, base).CacheTTL(request.Params.Name) <= 0 {
+				return next(ctx, request)
+			}
+			if v, ok := args[Argument]; ok {
+				if v != Bypass {
+					return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %s: must be %q", Argument, Bypass)), nil
+				}
+				args = maps.Clone(args)
+				delete(args, Argument)
+				request.Params.Arguments = args
+				ctx = WithBypass(ctx)
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// WithBypassArgument returns tool with the cache argument added to its
+// input schema, for tools whose responses are cached.
+func WithBypassArgument(tool mcp.Tool) mcp.Tool {
+	props := maps.Clone(tool.InputSchema.Properties)
+	if props == nil {
+		props = map[string]any{}
+	}
+	props[Argument] = map[string]any{
+		"type":        "string",
+		"enum":        []any{Bypass},
+		"description": "Set to bypass to fetch a fresh response instead of a cached one",
+	}
+	tool.InputSchema.Properties = props
+	return tool
+}
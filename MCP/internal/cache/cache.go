@@ -0,0 +1,89 @@
+// Package cache keeps successful upstream responses for a configurable time
+// per tool, so reference data such as symbol lists and profiles is not
+// fetched again on every call. Responses are stored in memory, in a bounded
+// LRU, or on disk, where they survive restarts.
+//
+// Cache keys include a hash of the caller's credentials, so a cached
+// response is only shared by sessions using the same key: what Finnhub
+// returns, such as a profile gated by plan, can depend on the key.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body"`
+	Expires    time.Time   `json:"expires"`
+}
+
+// Store is a cache backend. Get only returns entries that have not expired.
+type Store interface {
+	Get(key string) (*Entry, bool)
+	Set(key string, e *Entry)
+}
+
+type storeKey struct {
+	backend    string
+	dir        string
+	maxEntries int
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = map[storeKey]Store{}
+)
+
+// For returns the store configured by cfg, or nil when caching is off.
+// Stores are shared for the life of the process, like upstream transports,
+// so HTTP-mode sessions with their own APIConfig use the same cache.
+func For(cfg *config.APIConfig) (Store, error) {
+	if cfg.CacheBackend == config.CacheOff {
+		return nil, nil
+	}
+	key := storeKey{backend: cfg.CacheBackend, maxEntries: cfg.CacheMaxEntries}
+	if cfg.CacheBackend == config.CacheDisk {
+		key.dir = cfg.CacheDir
+	} else {
+		key.backend = config.CacheMemory
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if s, ok := stores[key]; ok {
+		return s, nil
+	}
+	var s Store
+	if key.backend == config.CacheDisk {
+		d, err := newDisk(key.dir)
+		if err != nil {
+			return nil, err
+		}
+		s = d
+	} else {
+		s = newMemory(key.maxEntries)
+	}
+	stores[key] = s
+	return s, nil
+}
+
+// Key returns the cache key of a request made with the credentials scope
+// identifies. Callers pass a hash of the credentials and the request without
+// them; the parts are hashed so keys have a fixed length and can be used as
+// file names.
+func Key(scope, method, url string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s %s\n", scope, method, url)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// sweepInterval is how often expired entries that are never read again
+	// are removed from the cache directory.
+	sweepInterval = 10 * time.Minute
+	// Temporary files older than this were left behind by an interrupted write.
+	staleTempAge = time.Hour
+)
+
+// disk is a store keeping one JSON file per entry in a directory. Expired
+// entries are removed when they are next read and by a periodic sweep.
+type disk struct {
+	dir string
+}
+
+func newDisk(dir string) (*disk, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	d := &disk{dir: dir}
+	// Stores live as long as the process, so the sweep is never stopped
+	go d.sweepEvery(sweepInterval)
+	return d, nil
+}
+
+func (d *disk) sweepEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		d.sweep(time.Now())
+		<-ticker.C
+	}
+}
+
+// sweep removes the entries that expired before now, entries that cannot be
+// decoded and stale temporary files.
+func (d *disk) sweep(now time.Time) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		slog.Warn("Failed to sweep cache directory", "error", err)
+		return
+	}
+	removed := 0
+	for _, f := range files {
+		name := f.Name()
+		path := filepath.Join(d.dir, name)
+		switch {
+		case strings.HasSuffix(name, ".tmp"):
+			if info, err := f.Info(); err == nil && now.Sub(info.ModTime()) > staleTempAge {
+				if os.Remove(path) == nil {
+					removed++
+				}
+			}
+		case strings.HasSuffix(name, ".json"):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var e struct {
+				Expires time.Time `json:"expires"`
+			}
+			if err := json.Unmarshal(data, &e); err != nil || !now.Before(e.Expires) {
+				if os.Remove(path) == nil {
+					removed++
+				}
+			}
+		}
+	}
+	if removed > 0 {
+		slog.Debug("Swept cache directory", "removed", removed)
+	}
+}
+
+func (d *disk) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+func (d *disk) Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || !time.Now().Before(e.Expires) {
+		os.Remove(d.path(key))
+		return nil, false
+	}
+	return &e, true
+}
+
+func (d *disk) Set(key string, e *Entry) {
+	data, err := json.Marshal(e)
+	if err == nil {
+		err = d.write(key, data)
+	}
+	if err != nil {
+		// A response that cannot be cached is fetched again next time
+		slog.Warn("Failed to cache response", "error", err)
+	}
+}
+
+// write replaces the file of key atomically, so concurrent readers never see
+// a partial entry.
+func (d *disk) write(key string, data []byte) error {
+	f, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
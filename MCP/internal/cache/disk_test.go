@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiskSweep(t *testing.T) {
+	dir := t.TempDir()
+	d := &disk{dir: dir}
+	now := time.Now()
+
+	d.Set("fresh", &Entry{StatusCode: 200, Body: []byte("{}"), Expires: now.Add(time.Hour)})
+	d.Set("expired", &Entry{StatusCode: 200, Body: []byte("{}"), Expires: now.Add(-time.Second)})
+	write := func(name, data string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("corrupt.json", "{", 0)
+	write("stale.123.tmp", "{", 2*staleTempAge)
+	write("writing.456.tmp", "{", 0)
+	write("other.txt", "kept", 0)
+
+	d.sweep(now)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	want := []string{"fresh.json", "other.txt", "writing.456.tmp"}
+	if !slices.Equal(names, want) {
+		t.Errorf("after sweep: %q, want %q", names, want)
+	}
+	if _, ok := d.Get("fresh"); !ok {
+		t.Error("fresh entry is gone")
+	}
+}
+
+func TestKeyScope(t *testing.T) {
+	a := Key("key-a", "GET", "https://finnhub.io/api/v1/stock/profile2?symbol=AAPL", nil)
+	if a != Key("key-a", "GET", "https://finnhub.io/api/v1/stock/profile2?symbol=AAPL", nil) {
+		t.Error("Key is not deterministic")
+	}
+	for _, other := range []string{
+		Key("key-b", "GET", "https://finnhub.io/api/v1/stock/profile2?symbol=AAPL", nil),
+		Key("key-a", "POST", "https://finnhub.io/api/v1/stock/profile2?symbol=AAPL", nil),
+		Key("key-a", "GET", "https://finnhub.io/api/v1/stock/profile2?symbol=MSFT", nil),
+		Key("key-a", "GET", "https://finnhub.io/api/v1/stock/profile2?symbol=AAPL", []byte("{}")),
+	} {
+		if other == a {
+			t.Errorf("different requests share key %s", a)
+		}
+	}
+}
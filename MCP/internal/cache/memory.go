@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// memory is an in-memory store that evicts the least recently used entry
+// once it holds maxEntries.
+type memory struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List               // Most recently used first
+	entries    map[string]*list.Element // Values are *item
+}
+
+type item struct {
+	key   string
+	entry *Entry
+}
+
+func newMemory(maxEntries int) *memory {
+	return &memory{maxEntries: maxEntries, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (m *memory) Get(key string) (*Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	it := el.Value.(*item)
+	if !time.Now().Before(it.entry.Expires) {
+		m.order.Remove(el)
+		delete(m.entries, key)
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	return it.entry, true
+}
+
+func (m *memory) Set(key string, e *Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		el.Value.(*item).entry = e
+		m.order.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&item{key: key, entry: e})
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*item).key)
+	}
+}
@@ -0,0 +1,167 @@
+// Package indicator describes the parameters of Finnhub's technical
+// indicators, which get_indicator takes in its indicator_fields argument.
+// swagger.json declares that argument as a free-form object and points to
+// Finnhub's indicator reference, so the tables here follow that reference
+// (the parameters are the TA-Lib ones) for get_indicator to advertise and
+// validate them.
+package indicator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/finnhub-api/mcp-server/internal/schema"
+)
+
+// properties holds the schema of every parameter an indicator can take.
+var properties = map[string]any{
+	"timeperiod":   periodField("Number of data points used to calculate the indicator."),
+	"timeperiod1":  periodField("First period (ULTOSC)."),
+	"timeperiod2":  periodField("Second period (ULTOSC)."),
+	"timeperiod3":  periodField("Third period (ULTOSC)."),
+	"fastperiod":   periodField("Fast moving average period."),
+	"slowperiod":   periodField("Slow moving average period."),
+	"signalperiod": periodField("Signal line period."),
+	"fastkperiod":  periodField("Fast %K period."),
+	"fastdperiod":  periodField("Fast %D period."),
+	"slowkperiod":  periodField("Slow %K period."),
+	"slowdperiod":  periodField("Slow %D period."),
+	"matype":       maTypeField("Moving average type."),
+	"fastdmatype":  maTypeField("Fast %D moving average type."),
+	"slowkmatype":  maTypeField("Slow %K moving average type."),
+	"slowdmatype":  maTypeField("Slow %D moving average type."),
+	"nbdev":        numberField("Number of standard deviations."),
+	"nbdevup":      numberField("Number of standard deviations for the upper band."),
+	"nbdevdn":      numberField("Number of standard deviations for the lower band."),
+	"vfactor":      numberField("Volume factor (T3)."),
+	"acceleration": numberField("Acceleration factor (SAR)."),
+	"maximum":      numberField("Maximum acceleration factor (SAR)."),
+	"seriestype": map[string]any{
+		"type":        "string",
+		"description": "Price series to use: o (open), h (high), l (low), c (close).",
+		"enum":        []any{"o", "h", "l", "c"},
+	},
+}
+
+// fields lists the parameters each indicator accepts.
+var fields = map[string][]string{
+	"sma":       {"timeperiod", "seriestype"},
+	"ema":       {"timeperiod", "seriestype"},
+	"wma":       {"timeperiod", "seriestype"},
+	"dema":      {"timeperiod", "seriestype"},
+	"tema":      {"timeperiod", "seriestype"},
+	"trima":     {"timeperiod", "seriestype"},
+	"kama":      {"timeperiod", "seriestype"},
+	"t3":        {"timeperiod", "vfactor", "seriestype"},
+	"midpoint":  {"timeperiod", "seriestype"},
+	"midprice":  {"timeperiod"},
+	"bbands":    {"timeperiod", "nbdevup", "nbdevdn", "matype", "seriestype"},
+	"sar":       {"acceleration", "maximum"},
+	"macd":      {"fastperiod", "slowperiod", "signalperiod", "seriestype"},
+	"apo":       {"fastperiod", "slowperiod", "matype", "seriestype"},
+	"ppo":       {"fastperiod", "slowperiod", "matype", "seriestype"},
+	"rsi":       {"timeperiod", "seriestype"},
+	"stoch":     {"fastkperiod", "slowkperiod", "slowkmatype", "slowdperiod", "slowdmatype"},
+	"stochf":    {"fastkperiod", "fastdperiod", "fastdmatype"},
+	"stochrsi":  {"timeperiod", "fastkperiod", "fastdperiod", "fastdmatype", "seriestype"},
+	"willr":     {"timeperiod"},
+	"adx":       {"timeperiod"},
+	"adxr":      {"timeperiod"},
+	"dx":        {"timeperiod"},
+	"plus_di":   {"timeperiod"},
+	"minus_di":  {"timeperiod"},
+	"plus_dm":   {"timeperiod"},
+	"minus_dm":  {"timeperiod"},
+	"cci":       {"timeperiod"},
+	"aroon":     {"timeperiod"},
+	"aroonosc":  {"timeperiod"},
+	"mfi":       {"timeperiod"},
+	"atr":       {"timeperiod"},
+	"natr":      {"timeperiod"},
+	"mom":       {"timeperiod", "seriestype"},
+	"roc":       {"timeperiod", "seriestype"},
+	"rocp":      {"timeperiod", "seriestype"},
+	"rocr":      {"timeperiod", "seriestype"},
+	"cmo":       {"timeperiod", "seriestype"},
+	"trix":      {"timeperiod", "seriestype"},
+	"linearreg": {"timeperiod", "seriestype"},
+	"stddev":    {"timeperiod", "nbdev", "seriestype"},
+	"var":       {"timeperiod", "nbdev", "seriestype"},
+	"ultosc":    {"timeperiod1", "timeperiod2", "timeperiod3"},
+	"adosc":     {"fastperiod", "slowperiod"},
+	"ad":        {},
+	"obv":       {},
+	"bop":       {},
+	"trange":    {},
+}
+
+// Schema is the schema of the indicator_fields argument.
+var Schema = map[string]any{
+	"type":                 "object",
+	"properties":           properties,
+	"additionalProperties": false,
+}
+
+// Description documents which parameters each indicator takes.
+func Description() string {
+	var b strings.Builder
+	b.WriteString("Indicator parameters, sent as query parameters. Omitted parameters use Finnhub's defaults. Supported parameters by indicator: ")
+	for i, name := range slices.Sorted(maps.Keys(fields)) {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		if len(fields[name]) == 0 {
+			fmt.Fprintf(&b, "%s: none", name)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s", name, strings.Join(fields[name], ", "))
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// Check validates the indicator_fields argument value for the indicator
+// name: every parameter must have the right type and be one the indicator
+// takes. Parameters cannot be checked for indicators missing from the
+// table, so those are rejected unless no parameters are given.
+func Check(name string, value any) error {
+	if err := schema.Validate(Schema, value); err != nil {
+		return err
+	}
+	given := value.(map[string]any)
+	if len(given) == 0 {
+		return nil
+	}
+	allowed, ok := fields[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("parameters are not supported for indicator %q (supported: %s)", name, strings.Join(slices.Sorted(maps.Keys(fields)), ", "))
+	}
+	for _, field := range slices.Sorted(maps.Keys(given)) {
+		if !slices.Contains(allowed, field) {
+			if len(allowed) == 0 {
+				return fmt.Errorf("%s does not take any parameters", name)
+			}
+			return fmt.Errorf("%s is not a parameter of %s (allowed: %s)", field, name, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+func periodField(description string) map[string]any {
+	return map[string]any{"type": "integer", "minimum": 1, "description": description}
+}
+
+func maTypeField(description string) map[string]any {
+	return map[string]any{
+		"type":        "integer",
+		"minimum":     0,
+		"maximum":     8,
+		"description": description + " 0=SMA, 1=EMA, 2=WMA, 3=DEMA, 4=TEMA, 5=TRIMA, 6=KAMA, 7=MAMA, 8=T3.",
+	}
+}
+
+func numberField(description string) map[string]any {
+	return map[string]any{"type": "number", "description": description}
+}
@@ -0,0 +1,58 @@
+package indicator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		indicator string
+		fields    any
+		wantErr   string
+	}{
+		{"known fields", "macd", map[string]any{"fastperiod": 12.0, "slowperiod": 26.0, "signalperiod": 9.0}, ""},
+		{"indicator name in upper case", "RSI", map[string]any{"timeperiod": 14.0, "seriestype": "c"}, ""},
+		{"no fields", "obv", map[string]any{}, ""},
+		{"no fields for an unknown indicator", "newindicator", map[string]any{}, ""},
+		{"field of another indicator", "rsi", map[string]any{"nbdevup": 2.0}, "nbdevup is not a parameter of rsi (allowed: timeperiod, seriestype)"},
+		{"indicator without fields", "obv", map[string]any{"timeperiod": 5.0}, "obv does not take any parameters"},
+		{"unknown field", "sma", map[string]any{"period": 5.0}, "period"},
+		{"unknown indicator", "newindicator", map[string]any{"timeperiod": 5.0}, `parameters are not supported for indicator "newindicator"`},
+		{"period as string", "sma", map[string]any{"timeperiod": "5"}, "timeperiod: expected integer, got string"},
+		{"fractional period", "sma", map[string]any{"timeperiod": 2.5}, "timeperiod: expected integer"},
+		{"period below 1", "sma", map[string]any{"timeperiod": 0.0}, "timeperiod"},
+		{"series outside the enum", "sma", map[string]any{"seriestype": "x"}, "seriestype: must be one of"},
+		{"moving average type above 8", "bbands", map[string]any{"matype": 9.0}, "matype"},
+		{"not an object", "sma", "timeperiod=5", "expected object, got string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.indicator, tt.fields)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTables(t *testing.T) {
+	// Every parameter an indicator lists has a schema
+	for name, fields := range fields {
+		for _, field := range fields {
+			if _, ok := properties[field]; !ok {
+				t.Errorf("%s lists %s, which has no schema", name, field)
+			}
+		}
+	}
+	if d := Description(); !strings.Contains(d, "macd: fastperiod, slowperiod, signalperiod, seriestype;") || !strings.Contains(d, "obv: none") {
+		t.Errorf("Description = %q", d)
+	}
+}
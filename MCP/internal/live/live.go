@@ -0,0 +1,210 @@
+// Package live keeps clients up to date on the resources they subscribed to.
+// Sessions subscribe to quote resources such as finnhub://stock/AAPL/quote;
+// the hub watches each symbol through a feed, either by polling get_quote or
+// through Finnhub's trades websocket, and sends
+// notifications/resources/updated when the quote changes.
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var quoteURI = regexp.MustCompile(`^finnhub://stock/([^/]+)/quote$`)
+
+// feed reports changes of a symbol's price until ctx is done.
+type feed interface {
+	start(ctx context.Context, cfg *config.APIConfig, symbol string, changed func()) error
+}
+
+// Hub tracks the subscriptions of every session.
+type Hub struct {
+	base  *config.APIConfig
+	quote server.ToolHandlerFunc
+	feed  feed
+	srv   *server.MCPServer
+
+	mu       sync.Mutex
+	sessions map[string]map[string]context.CancelFunc // Session ID -> URI -> stop watching
+}
+
+// New returns a hub that reads quotes with the get_quote handler and checks
+// market hours with the get_stock_market-status handler.
+func New(base *config.APIConfig, quote, marketStatus server.ToolHandlerFunc) *Hub {
+	h := &Hub{
+		base:     base,
+		quote:    quote,
+		sessions: map[string]map[string]context.CancelFunc{},
+	}
+	if base.QuoteFeed == config.QuoteFeedWebSocket {
+		h.feed = newTrades(base.QuoteWebSocketURL, base.QuotePollInterval)
+	} else {
+		h.feed = &poller{
+			interval: base.QuotePollInterval,
+			quote:    quote,
+			market:   newMarketHours(marketStatus),
+		}
+	}
+	return h
+}
+
+// Register installs the hub on srv through the hooks srv was created with:
+// subscriptions are checked before srv acknowledges them, and dropped on
+// unsubscribe and when the session ends.
+func (h *Hub) Register(srv *server.MCPServer, hooks *server.Hooks) {
+	h.srv = srv
+	hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+		raw, ok := message.(json.RawMessage)
+		if !ok {
+			return nil
+		}
+		var request mcp.SubscribeRequest
+		if err := json.Unmarshal(raw, &request); err != nil || request.Method != string(mcp.MethodResourcesSubscribe) {
+			return nil
+		}
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil {
+			return errors.New("subscriptions need a session")
+		}
+		return h.subscribe(ctx, session.SessionID(), request.Params.URI)
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			h.unsubscribe(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		h.drop(session.SessionID())
+	})
+}
+
+func (h *Hub) subscribe(ctx context.Context, sessionID, uri string) error {
+	m := quoteURI.FindStringSubmatch(uri)
+	if m == nil {
+		return fmt.Errorf("%s does not support subscriptions; only quotes such as finnhub://stock/AAPL/quote do", uri)
+	}
+	symbol, err := url.PathUnescape(m[1])
+	if err != nil || symbol == "" {
+		return fmt.Errorf("invalid symbol in %s", uri)
+	}
+	if fresh, err := h.reserve(sessionID, uri); err != nil || !fresh {
+		return err
+	}
+
+	// Reading the quote once checks the symbol, the session's tools and plan
+	// before anything is watched.
+	if _, err := readQuote(ctx, h.quote, symbol); err != nil {
+		h.unsubscribe(sessionID, uri)
+		return err
+	}
+	// Watching outlives the subscribe request but keeps its session and
+	// configuration.
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if !h.activate(sessionID, uri, cancel) {
+		cancel()
+		return nil
+	}
+	cfg := config.ForContext(ctx, h.base)
+	if err := h.feed.start(watchCtx, cfg, symbol, func() { h.send(sessionID, uri) }); err != nil {
+		h.unsubscribe(sessionID, uri)
+		return err
+	}
+	slog.Info("Resource subscribed", "session", sessionID, "uri", uri)
+	return nil
+}
+
+// reserve claims a subscription slot for uri. It returns false when the
+// session already holds one.
+func (h *Hub) reserve(sessionID, uri string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.sessions[sessionID]
+	if _, ok := subs[uri]; ok {
+		return false, nil
+	}
+	if len(subs) >= h.base.MaxSubscriptions {
+		return false, fmt.Errorf("subscription limit reached (%d per session); unsubscribe from another resource first", h.base.MaxSubscriptions)
+	}
+	if subs == nil {
+		subs = map[string]context.CancelFunc{}
+		h.sessions[sessionID] = subs
+	}
+	subs[uri] = nil
+	return true, nil
+}
+
+// activate attaches the watcher to a reserved slot. It returns false when
+// the session unsubscribed in the meantime.
+func (h *Hub) activate(sessionID, uri string, cancel context.CancelFunc) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.sessions[sessionID]
+	if current, ok := subs[uri]; !ok || current != nil {
+		return false
+	}
+	subs[uri] = cancel
+	return true
+}
+
+func (h *Hub) unsubscribe(sessionID, uri string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.sessions[sessionID]
+	if cancel := subs[uri]; cancel != nil {
+		cancel()
+	}
+	delete(subs, uri)
+	if len(subs) == 0 {
+		delete(h.sessions, sessionID)
+	}
+}
+
+// drop ends every subscription of a session.
+func (h *Hub) drop(sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, cancel := range h.sessions[sessionID] {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	delete(h.sessions, sessionID)
+}
+
+func (h *Hub) send(sessionID, uri string) {
+	err := h.srv.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	if errors.Is(err, server.ErrSessionNotFound) {
+		h.drop(sessionID)
+	} else if err != nil {
+		slog.Warn("Resource update not delivered", "session", sessionID, "uri", uri, "error", err)
+	}
+}
+
+// readQuote calls get_quote and returns its structured result.
+func readQuote(ctx context.Context, quote server.ToolHandlerFunc, symbol string) (map[string]any, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_quote"
+	request.Params.Arguments = map[string]any{"symbol": symbol}
+	return call(ctx, quote, request)
+}
+
+// call runs a tool handler and returns its structured result, or the tool
+// error as an error.
+func call(ctx context.Context, handler server.ToolHandlerFunc, request mcp.CallToolRequest) (map[string]any, error) {
+	res, err := handler(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return result.Value(res)
+}
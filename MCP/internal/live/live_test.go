@@ -0,0 +1,191 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// quotes is a get_quote handler that returns prices in turn and repeats the
+// last one.
+type quotes struct {
+	mu     sync.Mutex
+	prices []float64
+	calls  atomic.Int64
+}
+
+func (q *quotes) handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	q.calls.Add(1)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	price := q.prices[0]
+	if len(q.prices) > 1 {
+		q.prices = q.prices[1:]
+	}
+	return &mcp.CallToolResult{StructuredContent: map[string]any{"c": price, "t": 1700000000}}, nil
+}
+
+// settled waits until q has been called at least n times and then for a
+// few more polls, and returns the number of calls.
+func (q *quotes) settled(t *testing.T, n int64) int64 {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for q.calls.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("get_quote called %d times, want at least %d", q.calls.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	return q.calls.Load()
+}
+
+func TestPoller(t *testing.T) {
+	q := &quotes{prices: []float64{10, 10, 11, 11, 11, 12}}
+	p := &poller{interval: time.Millisecond, quote: q.handle, market: newMarketHours(nil)}
+	var changes atomic.Int64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := p.start(ctx, &config.APIConfig{}, "AAPL", func() { changes.Add(1) }); err != nil {
+		t.Fatal(err)
+	}
+
+	q.settled(t, 10)
+	// The first quote sets the baseline; only the two price changes count
+	if got := changes.Load(); got != 2 {
+		t.Errorf("%d changes reported, want 2", got)
+	}
+	cancel()
+	stopped := q.settled(t, 0)
+	if calls := q.settled(t, 0); calls != stopped {
+		t.Errorf("polling continued after cancel: %d calls, then %d", stopped, calls)
+	}
+}
+
+func TestPollerMarketClosed(t *testing.T) {
+	q := &quotes{prices: []float64{10, 11}}
+	var exchange atomic.Value
+	status := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		exchange.Store(request.GetArguments()["exchange"])
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"isOpen": false}}, nil
+	}
+	p := &poller{interval: time.Millisecond, quote: q.handle, market: newMarketHours(status)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.start(ctx, &config.APIConfig{}, "BMW.DE", func() {})
+
+	time.Sleep(20 * time.Millisecond)
+	if calls := q.calls.Load(); calls != 0 {
+		t.Errorf("get_quote called %d times while the market is closed", calls)
+	}
+	if exchange.Load() != "DE" {
+		t.Errorf("market status asked for %v, want DE", exchange.Load())
+	}
+}
+
+func TestExchangeOf(t *testing.T) {
+	tests := map[string]string{"AAPL": "US", "BRK.B": "US", "BMW.DE": "DE", "VOD.L": "L", "BINANCE:BTCUSDT": ""}
+	for symbol, want := range tests {
+		got, ok := exchangeOf(symbol)
+		if got != want || ok != (want != "") {
+			t.Errorf("exchangeOf(%q) = %q, %v, want %q", symbol, got, ok, want)
+		}
+	}
+}
+
+// clientSession is an initialized MCP session whose notifications go to a
+// channel.
+type clientSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *clientSession) Initialize()                                         {}
+func (s *clientSession) Initialized() bool                                   { return true }
+func (s *clientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *clientSession) SessionID() string                                   { return s.id }
+
+// newServer returns an MCP server with a hub polling q, and a registered
+// session on it.
+func newServer(t *testing.T, q *quotes) (*server.MCPServer, *clientSession, context.Context) {
+	t.Helper()
+	cfg := &config.APIConfig{QuoteFeed: config.QuoteFeedPoll, QuotePollInterval: time.Millisecond, MaxSubscriptions: 2}
+	hooks := &server.Hooks{}
+	srv := server.NewMCPServer("test", "1.0", server.WithResourceCapabilities(true, false), server.WithHooks(hooks))
+	New(cfg, q.handle, nil).Register(srv, hooks)
+
+	session := &clientSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 100)}
+	if err := srv.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	return srv, session, srv.WithContext(context.Background(), session)
+}
+
+func request(t *testing.T, srv *server.MCPServer, ctx context.Context, method, uri string) mcp.JSONRPCMessage {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": map[string]any{"uri": uri}})
+	return srv.HandleMessage(ctx, raw)
+}
+
+func TestSubscribe(t *testing.T) {
+	// Subscribing reads the first quote, polling the second and third
+	q := &quotes{prices: []float64{10, 10, 11}}
+	srv, session, ctx := newServer(t, q)
+
+	const uri = "finnhub://stock/AAPL/quote"
+	if res, ok := request(t, srv, ctx, "resources/subscribe", uri).(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("subscribe failed: %+v", res)
+	}
+	select {
+	case n := <-session.notifications:
+		if n.Method != mcp.MethodNotificationResourceUpdated || n.Params.AdditionalFields["uri"] != uri {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification after the price changed")
+	}
+
+	request(t, srv, ctx, "resources/unsubscribe", uri)
+	stopped := q.settled(t, 0)
+	if calls := q.settled(t, 0); calls != stopped {
+		t.Errorf("polling continued after unsubscribe: %d calls, then %d", stopped, calls)
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	q := &quotes{prices: []float64{10}}
+	srv, _, ctx := newServer(t, q)
+
+	for _, uri := range []string{"finnhub://stock/AAPL/profile", "finnhub://stock//quote"} {
+		if _, ok := request(t, srv, ctx, "resources/subscribe", uri).(mcp.JSONRPCError); !ok {
+			t.Errorf("subscription to %s accepted", uri)
+		}
+	}
+	request(t, srv, ctx, "resources/subscribe", "finnhub://stock/AAPL/quote")
+	request(t, srv, ctx, "resources/subscribe", "finnhub://stock/MSFT/quote")
+	if _, ok := request(t, srv, ctx, "resources/subscribe", "finnhub://stock/TSLA/quote").(mcp.JSONRPCError); !ok {
+		t.Error("subscription beyond the limit accepted")
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	q := &quotes{prices: []float64{10}}
+	srv, session, ctx := newServer(t, q)
+	request(t, srv, ctx, "resources/subscribe", "finnhub://stock/AAPL/quote")
+	request(t, srv, ctx, "resources/subscribe", "finnhub://stock/MSFT/quote")
+	q.settled(t, 4)
+
+	// A deleted or evicted session ends its subscriptions
+	srv.UnregisterSession(context.Background(), session.id)
+	stopped := q.settled(t, 0)
+	if calls := q.settled(t, 0); calls != stopped {
+		t.Errorf("polling continued after the session ended: %d calls, then %d", stopped, calls)
+	}
+}
@@ -0,0 +1,125 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// marketStatusTTL is how long a market's open or closed status is trusted
+// before get_stock_market-status is asked again.
+const marketStatusTTL = time.Minute
+
+// poller is the polling feed: it reads the quote of each subscribed symbol
+// every interval while its market is open.
+type poller struct {
+	interval time.Duration
+	quote    server.ToolHandlerFunc
+	market   *marketHours
+}
+
+func (p *poller) start(ctx context.Context, cfg *config.APIConfig, symbol string, changed func()) error {
+	go p.run(config.NewContext(ctx, cfg), symbol, changed)
+	return nil
+}
+
+func (p *poller) run(ctx context.Context, symbol string, changed func()) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		if p.market.open(ctx, symbol) {
+			q, err := readQuote(ctx, p.quote, symbol)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				slog.Warn("Quote polling failed", "symbol", symbol, "error", err)
+			default:
+				// The price and the time of the last trade identify a quote
+				current := fmt.Sprint(q["c"], q["t"])
+				if last != "" && current != last {
+					changed()
+				}
+				last = current
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// marketHours caches whether markets are open, per exchange.
+type marketHours struct {
+	status server.ToolHandlerFunc
+
+	mu     sync.Mutex
+	states map[string]marketState
+}
+
+type marketState struct {
+	open    bool
+	checked time.Time
+}
+
+func newMarketHours(status server.ToolHandlerFunc) *marketHours {
+	return &marketHours{status: status, states: map[string]marketState{}}
+}
+
+// open reports whether the market of symbol is open. Symbols traded around
+// the clock, and markets whose status cannot be read, count as open.
+func (m *marketHours) open(ctx context.Context, symbol string) bool {
+	exchange, ok := exchangeOf(symbol)
+	if !ok || m.status == nil {
+		return true
+	}
+	m.mu.Lock()
+	state, ok := m.states[exchange]
+	m.mu.Unlock()
+	if ok && time.Since(state.checked) < marketStatusTTL {
+		return state.open
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_stock_market-status"
+	request.Params.Arguments = map[string]any{"exchange": exchange}
+	state = marketState{open: true, checked: time.Now()}
+	if status, err := call(ctx, m.status, request); err != nil {
+		slog.Warn("Market status unavailable", "exchange", exchange, "error", err)
+	} else if isOpen, ok := status["isOpen"].(bool); ok {
+		state.open = isOpen
+	}
+	m.mu.Lock()
+	if previous, ok := m.states[exchange]; ok && previous.open && !state.open {
+		slog.Info("Market closed; pausing quote polling", "exchange", exchange)
+	}
+	m.states[exchange] = state
+	m.mu.Unlock()
+	return state.open
+}
+
+// shareClasses are the suffixes of US share classes, e.g. BRK.B, as opposed
+// to exchange codes such as BMW.DE or VOD.L.
+var shareClasses = map[string]bool{"A": true, "B": true, "C": true}
+
+// exchangeOf returns the exchange code get_stock_market-status expects for
+// a symbol. Crypto and forex symbols (BINANCE:BTCUSDT) have none.
+func exchangeOf(symbol string) (string, bool) {
+	if strings.Contains(symbol, ":") {
+		return "", false
+	}
+	if i := strings.LastIndex(symbol, "."); i >= 0 && !shareClasses[symbol[i+1:]] {
+		return symbol[i+1:], true
+	}
+	return "US", true
+}
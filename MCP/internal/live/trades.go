@@ -0,0 +1,215 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/auth"
+)
+
+// trades is the websocket feed. Finnhub allows one trades connection per API
+// key, so every subscription made with the same key shares one stream.
+type trades struct {
+	url      string
+	interval time.Duration // Minimum gap between updates of a symbol
+
+	mu      sync.Mutex
+	streams map[string]*tradeStream // By API key
+}
+
+func newTrades(url string, interval time.Duration) *trades {
+	return &trades{url: url, interval: interval, streams: map[string]*tradeStream{}}
+}
+
+func (t *trades) start(ctx context.Context, cfg *config.APIConfig, symbol string, changed func()) error {
+	if cfg.APIKey == "" {
+		return errors.New("quote updates come from the trades websocket, which needs an API key")
+	}
+	w := &watcher{changed: changed, interval: t.interval}
+
+	t.mu.Lock()
+	s, ok := t.streams[cfg.APIKey]
+	if !ok {
+		streamCtx, cancel := context.WithCancel(context.Background())
+		s = &tradeStream{url: t.url, key: cfg.APIKey, cancel: cancel, watchers: map[string]map[*watcher]bool{}}
+		t.streams[cfg.APIKey] = s
+		go s.run(streamCtx)
+	}
+	s.add(symbol, w)
+	t.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if s.remove(symbol, w) == 0 {
+			s.cancel()
+			delete(t.streams, s.key)
+		}
+	})
+	return nil
+}
+
+// watcher is one subscription to a symbol's trades.
+type watcher struct {
+	changed  func()
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// trade reports a trade, at most once per interval.
+func (w *watcher) trade() {
+	w.mu.Lock()
+	due := time.Since(w.last) >= w.interval
+	if due {
+		w.last = time.Now()
+	}
+	w.mu.Unlock()
+	if due {
+		w.changed()
+	}
+}
+
+// tradeStream is the connection of one API key. It reconnects until every
+// watcher is gone.
+type tradeStream struct {
+	url    string
+	key    string
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	conn     *websocket.Conn // nil while connecting
+	watchers map[string]map[*watcher]bool
+}
+
+type tradeMessage struct {
+	Type   string `json:"type"`
+	Symbol string `json:"symbol,omitempty"`
+	Msg    string `json:"msg,omitempty"`
+	Data   []struct {
+		S string `json:"s"`
+	} `json:"data,omitempty"`
+}
+
+func (s *tradeStream) add(symbol string, w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers[symbol] == nil {
+		s.watchers[symbol] = map[*watcher]bool{}
+		s.send("subscribe", symbol)
+	}
+	s.watchers[symbol][w] = true
+}
+
+// remove drops a watcher and returns the number of symbols still watched.
+func (s *tradeStream) remove(symbol string, w *watcher) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watchers[symbol], w)
+	if len(s.watchers[symbol]) == 0 {
+		delete(s.watchers, symbol)
+		s.send("unsubscribe", symbol)
+	}
+	return len(s.watchers)
+}
+
+// send writes a subscription change when connected; symbols added while
+// connecting are subscribed once the connection is up. Callers hold s.mu.
+func (s *tradeStream) send(action, symbol string) {
+	if s.conn == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := wsjson.Write(ctx, s.conn, tradeMessage{Type: action, Symbol: symbol}); err != nil {
+		// The read loop sees the broken connection and reconnects
+		slog.Warn("Trades websocket: "+action+" failed", "symbol", symbol, "error", err)
+	}
+}
+
+func (s *tradeStream) run(ctx context.Context) {
+	delay := time.Second
+	for {
+		connected, err := s.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = time.Second
+		}
+		slog.Warn("Trades websocket disconnected", "error", s.redact(err), "retry_in", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, time.Minute)
+	}
+}
+
+// serve connects, subscribes to the watched symbols and dispatches trades
+// until the connection fails. It reports whether the connection was made.
+func (s *tradeStream) serve(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.Dial(ctx, s.url+"?"+auth.TokenParam+"="+url.QueryEscape(s.key), nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.CloseNow()
+
+	s.mu.Lock()
+	s.conn = conn
+	for symbol := range s.watchers {
+		s.send("subscribe", symbol)
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+	}()
+
+	for {
+		var msg tradeMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return true, err
+		}
+		switch msg.Type {
+		case "trade":
+			seen := map[string]bool{}
+			for _, t := range msg.Data {
+				if !seen[t.S] {
+					seen[t.S] = true
+					s.dispatch(t.S)
+				}
+			}
+		case "error":
+			slog.Warn("Trades websocket error", "message", msg.Msg)
+		}
+	}
+}
+
+func (s *tradeStream) dispatch(symbol string) {
+	s.mu.Lock()
+	watchers := make([]*watcher, 0, len(s.watchers[symbol]))
+	for w := range s.watchers[symbol] {
+		watchers = append(watchers, w)
+	}
+	s.mu.Unlock()
+	for _, w := range watchers {
+		w.trade()
+	}
+}
+
+// redact keeps the API key out of connection errors, which may quote the URL.
+func (s *tradeStream) redact(err error) string {
+	return strings.ReplaceAll(err.Error(), url.QueryEscape(s.key), "REDACTED")
+}
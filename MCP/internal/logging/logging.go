@@ -0,0 +1,174 @@
+// Package logging sets up the server's structured logs. Records are written
+// to stderr as text or JSON and, when logged with the context of an MCP
+// request, also sent to that request's client as notifications/message at
+// the level the client chose with logging/setLevel. Credentials are masked
+// in both: in URLs, headers and attributes named after them.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// loggerName names the server's messages to clients.
+const loggerName = "finnhub"
+
+// secretKeys are attribute names whose values are always masked.
+var secretKeys = []string{"token", "api_key", "apikey", "bearer_token", "basic_auth", "authorization", "password", strings.ToLower(auth.TokenHeader)}
+
+// Setup makes a logger configured by cfg the default, for slog and for the
+// log package, whose output it takes over.
+func Setup(cfg *config.APIConfig) {
+	slog.SetDefault(slog.New(newHandler(os.Stderr, cfg)))
+}
+
+// newHandler returns the handler Setup installs, writing to w.
+func newHandler(w io.Writer, cfg *config.APIConfig) *handler {
+	opts := &slog.HandlerOptions{
+		Level: cfg.LogLevel,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			return redact(a)
+		},
+	}
+	var out slog.Handler = slog.NewTextHandler(w, opts)
+	if cfg.LogFormat == config.LogFormatJSON {
+		out = slog.NewJSONHandler(w, opts)
+	}
+	return &handler{out: out, level: cfg.LogLevel}
+}
+
+// handler writes records of at least level to out and forwards records to
+// the client of the request they were logged for.
+type handler struct {
+	out   slog.Handler
+	level slog.Level
+
+	// For forwarding, which has to render attributes itself
+	attrs  []slog.Attr // Added with WithAttrs, keys prefixed with their group
+	prefix string      // Groups opened with WithGroup, joined by dots
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.level {
+		return true
+	}
+	_, _, ok := client(ctx, level)
+	return ok
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.level {
+		if err := h.out.Handle(ctx, r); err != nil {
+			return err
+		}
+	}
+	srv, level, ok := client(ctx, r.Level)
+	if !ok {
+		return nil
+	}
+	data := map[string]any{"message": auth.RedactText(r.Message)}
+	for _, a := range h.attrs {
+		data[a.Key] = value(redact(a).Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		data[h.prefix+a.Key] = value(redact(a).Value)
+		return true
+	})
+	// The client may have gone away; the log line is written anyway
+	_ = srv.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(level, loggerName, data))
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	forwarded := slices.Clone(h.attrs)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		forwarded = append(forwarded, a)
+	}
+	return &handler{out: h.out.WithAttrs(attrs), level: h.level, attrs: forwarded, prefix: h.prefix}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{out: h.out.WithGroup(name), level: h.level, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// client returns the server and the MCP level of a record of level logged
+// with ctx, if ctx belongs to a client that asked for records of that level.
+func client(ctx context.Context, level slog.Level) (*server.MCPServer, mcp.LoggingLevel, bool) {
+	srv := server.ServerFromContext(ctx)
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+	if srv == nil || !ok || !session.Initialized() {
+		return nil, "", false
+	}
+	var mcpLevel mcp.LoggingLevel
+	switch {
+	case level >= slog.LevelError:
+		mcpLevel = mcp.LoggingLevelError
+	case level >= slog.LevelWarn:
+		mcpLevel = mcp.LoggingLevelWarning
+	case level >= slog.LevelInfo:
+		mcpLevel = mcp.LoggingLevelInfo
+	default:
+		mcpLevel = mcp.LoggingLevelDebug
+	}
+	return srv, mcpLevel, mcpLevel.ShouldSendTo(session.GetLogLevel())
+}
+
+// redact masks the credentials an attribute may carry.
+func redact(a slog.Attr) slog.Attr {
+	if slices.Contains(secretKeys, strings.ToLower(a.Key)) {
+		return slog.String(a.Key, "REDACTED")
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, auth.RedactText(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = redact(member)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case http.Header:
+			return slog.Any(a.Key, auth.RedactHeader(x))
+		case error:
+			return slog.String(a.Key, auth.RedactText(x.Error()))
+		case fmt.Stringer:
+			// e.g. *url.URL
+			return slog.String(a.Key, auth.RedactText(x.String()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// value converts an attribute value for a JSON notification.
+func value(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindGroup:
+		group := make(map[string]any, len(v.Group()))
+		for _, a := range v.Group() {
+			group[a.Key] = value(a.Value)
+		}
+		return group
+	}
+	return v.Any()
+}
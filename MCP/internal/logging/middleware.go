@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware logs one line per tool call with its tool, duration, status
+// and the size of its result. Failed calls are logged as warnings with the
+// error. The line carries the call's trace ID when it is traced.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		res, err := next(ctx, request)
+
+		bytes, text := 0, ""
+		if res != nil {
+			for _, c := range res.Content {
+				if tc, ok := mcp.AsTextContent(c); ok {
+					bytes += len(tc.Text)
+					if res.IsError {
+						text += tc.Text
+					}
+				}
+			}
+		}
+		level, status, failure := slog.LevelInfo, "ok", ""
+		switch {
+		case err != nil:
+			level, status, failure = slog.LevelWarn, "error", err.Error()
+		case res == nil || res.IsError:
+			level, status, failure = slog.LevelWarn, "error", text
+		}
+
+		attrs := []slog.Attr{
+			slog.String("tool", request.Params.Name),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("status", status),
+			slog.Int("bytes", bytes),
+		}
+		if failure != "" {
+			attrs = append(attrs, slog.String("error", failure))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+		}
+		slog.LogAttrs(ctx, level, "Tool call", attrs...)
+		return res, err
+	}
+}
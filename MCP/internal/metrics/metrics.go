@@ -0,0 +1,137 @@
+// Package metrics collects Prometheus metrics about tool calls and the
+// upstream requests they make, served on /metrics in HTTP mode. Metrics are
+// process-wide: they cover every session, whatever key it uses.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "finnhub_mcp"
+
+var (
+	toolCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_calls_total",
+		Help:      "Tool calls by tool and outcome (ok or error).",
+	}, []string{"tool", "outcome"})
+	toolDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "Duration of tool calls by tool.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"tool"})
+
+	upstreamResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_responses_total",
+		Help:      "Upstream attempts by tool and HTTP status code, or error when no response arrived.",
+	}, []string{"tool", "code"})
+	upstreamRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_retries_total",
+		Help:      "Upstream attempts repeated after a transient failure, by tool.",
+	}, []string{"tool"})
+	upstreamInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_in_flight",
+		Help:      "Upstream requests currently being sent or read.",
+	})
+	rateLimitWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time upstream attempts were queued behind the client-side rate limiter.",
+		Buckets:   []float64{0, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	})
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Response cache lookups of cached tools by tool and result (hit or miss).",
+	}, []string{"tool", "result"})
+	coalesced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_coalesced_total",
+		Help:      "Calls answered by an upstream request another call had already sent, by tool.",
+	}, []string{"tool"})
+)
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware counts and times tool calls. Calls rejected before they reach
+// the tool, e.g. for the key's plan, count as errors.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		res, err := next(ctx, request)
+		tool := request.Params.Name
+		toolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+		outcome := "ok"
+		if err != nil || res == nil || res.IsError {
+			outcome = "error"
+		}
+		toolCalls.WithLabelValues(tool, outcome).Inc()
+		return res, err
+	}
+}
+
+// Sessions reports the number of active MCP sessions, as returned by count.
+// It may only be called once.
+func Sessions(count func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sessions_active",
+		Help:      "Active MCP sessions.",
+	}, func() float64 { return float64(count()) })
+}
+
+// UpstreamStarted records an upstream attempt being sent and returns a
+// function recording its outcome: the status code, or 0 when the attempt
+// failed without a response.
+func UpstreamStarted(tool string) func(code int) {
+	upstreamInFlight.Inc()
+	return func(code int) {
+		upstreamInFlight.Dec()
+		label := "error"
+		if code > 0 {
+			label = strconv.Itoa(code)
+		}
+		upstreamResponses.WithLabelValues(tool, label).Inc()
+	}
+}
+
+// Retried records an upstream attempt about to be repeated.
+func Retried(tool string) {
+	upstreamRetries.WithLabelValues(tool).Inc()
+}
+
+// RateLimited records how long an upstream attempt was queued behind the
+// rate limiter.
+func RateLimited(wait time.Duration) {
+	rateLimitWait.Observe(wait.Seconds())
+}
+
+// CacheLookup records a response cache lookup.
+func CacheLookup(tool string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(tool, result).Inc()
+}
+
+// Coalesced records a call answered by another call's upstream request.
+func Coalesced(tool string) {
+	coalesced.WithLabelValues(tool).Inc()
+}
@@ -0,0 +1,70 @@
+// Package params turns tool arguments into Finnhub query parameters.
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query collects the named arguments into URL query parameters. Arguments
+// that are absent or null are left out.
+func Query(args map[string]any, names ...string) url.Values {
+	query := url.Values{}
+	for _, name := range names {
+		val, ok := args[name]
+		if !ok || val == nil {
+			continue
+		}
+		query.Set(name, Format(val))
+	}
+	return query
+}
+
+// Merge adds every field of an object argument to query, for object
+// arguments whose fields are sent as individual query parameters.
+func Merge(query url.Values, fields map[string]any) {
+	for name, val := range fields {
+		if val != nil {
+			query.Set(name, Format(val))
+		}
+	}
+}
+
+// Format renders an argument the way the API expects it. JSON numbers arrive
+// as float64, so whole numbers are printed without a fraction or exponent
+// (UNIX timestamps stay 1700000000 rather than 1.7e+09). Booleans become
+// true/false and arrays are joined with commas.
+func Format(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return Format(float64(v))
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = Format(e)
+		}
+		return strings.Join(parts, ",")
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return fmt.Sprint(val)
+}
@@ -0,0 +1,54 @@
+package params
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		want string
+	}{
+		{"string", "AAPL", "AAPL"},
+		{"whole float", 5.0, "5"},
+		{"timestamp", 1700000000.0, "1700000000"},
+		{"negative whole float", -3.0, "-3"},
+		{"zero", 0.0, "0"},
+		{"fraction", 0.25, "0.25"},
+		{"small fraction", 0.0000001, "0.0000001"},
+		{"beyond exact integers", 1e20, "100000000000000000000"},
+		{"float32", float32(2), "2"},
+		{"int", 42, "42"},
+		{"int64", int64(1700000000000), "1700000000000"},
+		{"bool", true, "true"},
+		{"json.Number", json.Number("1.50"), "1.50"},
+		{"array", []any{"AAPL", 2.0, false}, "AAPL,2,false"},
+		{"string slice", []string{"a", "b"}, "a,b"},
+		{"other", struct{ A int }{1}, "{1}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.val); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	args := map[string]any{"symbol": "BRK.B & co", "from": 1700000000.0, "to": nil, "extra": "x"}
+	query := Query(args, "symbol", "from", "to", "resolution")
+	if got, want := query.Encode(), "from=1700000000&symbol=BRK.B+%26+co"; got != want {
+		t.Errorf("Query = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	query := Query(map[string]any{"symbol": "AAPL", "indicator": "sma"}, "symbol", "indicator")
+	Merge(query, map[string]any{"timeperiod": 14.0, "seriestype": "c", "nbdev": 1.5, "matype": nil})
+	if got, want := query.Encode(), "indicator=sma&nbdev=1.5&seriestype=c&symbol=AAPL&timeperiod=14"; got != want {
+		t.Errorf("Merge = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+// Package plan models Finnhub's subscription plans: which tier each endpoint
+// requires, which tiers a plan includes, and the error reported when the API
+// rejects a key whose plan lacks an endpoint.
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Tier is the access level an endpoint requires, as stated by the premium
+// field in swagger.json.
+type Tier string
+
+const (
+	Free         Tier = "free"
+	Premium      Tier = "premium"       // "Premium Access Required"
+	Fundamental  Tier = "fundamental"   // "Accessible with Fundamental data or All in One subscription."
+	Fundamental2 Tier = "fundamental-2" // "Accessible with Fundamental 2 or All in One subscription."
+	Enterprise   Tier = "enterprise"    // "Access approved on a case by case basis"
+)
+
+// Access is what an endpoint requires: its tier, and the documentation's
+// note about it, such as "Premium Access Required" or the history a free
+// key gets.
+type Access struct {
+	Tier Tier
+	Note string
+}
+
+// Plan is the subscription of the API key in use.
+type Plan string
+
+// Unknown is the zero Plan, used when no plan is configured.
+const Unknown Plan = ""
+
+var plans = []Plan{"free", "premium", "fundamental", "fundamental-2", "all-in-one", "enterprise"}
+
+// tiers lists the tiers each plan includes. Plans are not a scale: premium
+// market data and the two fundamental data packages are sold separately,
+// and all-in-one covers all of them but not endpoints granted case by case.
+var tiers = map[Plan][]Tier{
+	"free":          {Free},
+	"premium":       {Free, Premium},
+	"fundamental":   {Free, Fundamental},
+	"fundamental-2": {Free, Fundamental2},
+	"all-in-one":    {Free, Premium, Fundamental, Fundamental2},
+	"enterprise":    {Free, Premium, Fundamental, Fundamental2, Enterprise},
+}
+
+// Parse validates a PLAN setting. The empty string is Unknown.
+func Parse(s string) (Plan, error) {
+	p := Plan(strings.ToLower(strings.TrimSpace(s)))
+	if p == Unknown || slices.Contains(plans, p) {
+		return p, nil
+	}
+	names := make([]string, len(plans))
+	for i, p := range plans {
+		names[i] = string(p)
+	}
+	return Unknown, fmt.Errorf("unknown plan %q (expected one of %s)", s, strings.Join(names, ", "))
+}
+
+// Allows reports whether the plan includes endpoints of tier t. An Unknown
+// plan allows everything, leaving the decision to the API.
+func (p Plan) Allows(t Tier) bool {
+	if p == Unknown {
+		return true
+	}
+	return slices.Contains(tiers[p], t)
+}
+
+// Denied reports whether an API response is Finnhub's answer to a key whose
+// plan does not include the endpoint.
+func Denied(statusCode int, body []byte) bool {
+	return statusCode == http.StatusForbidden && bytes.Contains(body, []byte("You don't have access to this resource"))
+}
+
+// Requirement describes the access in a sentence, e.g. "Requires the
+// premium plan (Premium Access Required)."
+func (a Access) Requirement() string {
+	if a.Tier == Free {
+		if a.Note == "" {
+			return "Available on the free plan."
+		}
+		return fmt.Sprintf("Available on the free plan (free tier: %s).", strings.TrimSuffix(a.Note, "."))
+	}
+	if a.Note == "" {
+		return fmt.Sprintf("Requires the %s plan.", a.Tier)
+	}
+	return fmt.Sprintf("Requires the %s plan (%s).", a.Tier, strings.TrimSuffix(a.Note, "."))
+}
+
+// Error explains an endpoint rejected for lack of access.
+type Error struct {
+	Tool   string
+	Access Access
+	Plan   Plan // The configured plan, if known
+}
+
+func (e *Error) Error() string {
+	if e.Access.Tier == Free {
+		// Free endpoints limit history or detail rather than access
+		msg := fmt.Sprintf("%s is available on the free plan, but Finnhub denied access to the requested data", e.Tool)
+		if e.Access.Note != "" {
+			msg += fmt.Sprintf(" (free tier: %s)", strings.TrimSuffix(e.Access.Note, "."))
+		}
+		return msg + ". Narrow the request or upgrade at https://finnhub.io/pricing."
+	}
+	msg := fmt.Sprintf("%s requires the %s Finnhub plan", e.Tool, e.Access.Tier)
+	if e.Access.Note != "" {
+		msg += fmt.Sprintf(" (%s)", strings.TrimSuffix(e.Access.Note, "."))
+	}
+	switch {
+	case e.Plan != Unknown && e.Plan.Allows(e.Access.Tier):
+		// The configured plan is wrong about the key
+		msg += fmt.Sprintf(", which this API key does not include although PLAN says %s", e.Plan)
+	case e.Plan != Unknown:
+		msg += fmt.Sprintf(", which the configured %s plan does not include", e.Plan)
+	default:
+		msg += ", which this API key does not include"
+	}
+	return msg + ". Use a tool available on your plan or upgrade at https://finnhub.io/pricing."
+}
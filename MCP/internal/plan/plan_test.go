@@ -0,0 +1,95 @@
+package plan
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Plan{"": Unknown, " Premium ": "premium", "ALL-IN-ONE": "all-in-one", "fundamental-2": "fundamental-2"} {
+		if got, err := Parse(in); err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("gold"); err == nil || !strings.Contains(err.Error(), "free, premium, fundamental") {
+		t.Errorf("Parse(gold) error = %v", err)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	all := []Tier{Free, Premium, Fundamental, Fundamental2, Enterprise}
+	tests := []struct {
+		plan Plan
+		want []Tier
+	}{
+		{Unknown, all},
+		{"free", []Tier{Free}},
+		{"premium", []Tier{Free, Premium}},
+		{"fundamental", []Tier{Free, Fundamental}},
+		{"fundamental-2", []Tier{Free, Fundamental2}},
+		{"all-in-one", []Tier{Free, Premium, Fundamental, Fundamental2}},
+		{"enterprise", all},
+	}
+	for _, tt := range tests {
+		var got []Tier
+		for _, tier := range all {
+			if tt.plan.Allows(tier) {
+				got = append(got, tier)
+			}
+		}
+		if strings.Join(tiersString(got), ",") != strings.Join(tiersString(tt.want), ",") {
+			t.Errorf("plan %q allows %v, want %v", tt.plan, got, tt.want)
+		}
+	}
+}
+
+func tiersString(tiers []Tier) []string {
+	s := make([]string, len(tiers))
+	for i, t := range tiers {
+		s[i] = string(t)
+	}
+	return s
+}
+
+func TestDenied(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bool
+	}{
+		{"plan denial", http.StatusForbidden, `{"error":"You don't have access to this resource."}`, true},
+		{"other 403", http.StatusForbidden, `{"error":"Invalid API key."}`, false},
+		{"message with other status", http.StatusTooManyRequests, `{"error":"You don't have access to this resource."}`, false},
+		{"ok", http.StatusOK, `{}`, false},
+	}
+	for _, tt := range tests {
+		if got := Denied(tt.status, []byte(tt.body)); got != tt.want {
+			t.Errorf("%s: Denied = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	premium := Access{Tier: Premium, Note: "Premium Access Required"}
+	tests := []struct {
+		name string
+		err  Error
+		want string
+	}{
+		{"no plan", Error{Tool: "get_stock_candle", Access: premium},
+			"get_stock_candle requires the premium Finnhub plan (Premium Access Required), which this API key does not include."},
+		{"plan without tier", Error{Tool: "get_stock_candle", Access: premium, Plan: "fundamental"},
+			"which the configured fundamental plan does not include."},
+		{"plan with tier", Error{Tool: "get_stock_candle", Access: premium, Plan: "premium"},
+			"which this API key does not include although PLAN says premium."},
+		{"free tier limit", Error{Tool: "get_company_news", Access: Access{Tier: Free, Note: "1 year of historical news."}},
+			"get_company_news is available on the free plan, but Finnhub denied access to the requested data (free tier: 1 year of historical news)."},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: %q, want it to contain %q", tt.name, got, tt.want)
+		}
+	}
+}
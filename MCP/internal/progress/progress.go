@@ -0,0 +1,220 @@
+// Package progress sends notifications/progress for tool calls whose request
+// carries a progress token. The upstream request path reports what a call is
+// waiting for (the rate limiter, a retry) and how much of the response has
+// arrived, which is what takes time in large pulls such as tick data.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// interval is the minimum gap between two download notifications of a call.
+const interval = 250 * time.Millisecond
+
+type reporterKey struct{}
+
+// Reporter sends the progress notifications of one tool call. Progress is
+// counted in bytes received; status messages advance it by one, since every
+// notification must report more progress than the previous one. A nil
+// Reporter sends nothing.
+type Reporter struct {
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+
+	mu       sync.Mutex
+	progress float64
+	reported float64 // Progress of the last notification, -1 before the first
+	sent     time.Time
+
+	// A shared Reporter has no client of its own and passes everything on
+	// to the Reporters of the calls sharing the work, with each call's
+	// share of the download in progress
+	members     map[*Reporter]*download
+	downloading bool
+	remaining   int64 // Bytes left to download, -1 when unknown
+}
+
+// Middleware attaches a Reporter to tool calls made with a progress token.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		srv := server.ServerFromContext(ctx)
+		if meta := request.Params.Meta; meta != nil && meta.ProgressToken != nil && srv != nil {
+			ctx = context.WithValue(ctx, reporterKey{}, &Reporter{ctx: ctx, srv: srv, token: meta.ProgressToken, reported: -1})
+		}
+		return next(ctx, request)
+	}
+}
+
+// FromContext returns the Reporter of the tool call ctx belongs to, or nil.
+func FromContext(ctx context.Context) *Reporter {
+	r, _ := ctx.Value(reporterKey{}).(*Reporter)
+	return r
+}
+
+// Shared returns a Reporter for work done once on behalf of several tool
+// calls, such as an upstream request they share. It reports to the Reporter
+// of every call added with Add.
+func Shared() *Reporter {
+	return &Reporter{members: map[*Reporter]*download{}}
+}
+
+// NewContext returns a copy of ctx carrying r.
+func NewContext(ctx context.Context, r *Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, r)
+}
+
+// Add makes a shared Reporter report to member as well. A member added
+// while a response is downloading counts the rest of it. Calls that have
+// ended get nothing more.
+func (r *Reporter) Add(member *Reporter) {
+	if r == nil || member == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var d *download
+	if r.downloading {
+		d = member.download(nil, r.remaining)
+	}
+	r.members[member] = d
+}
+
+// Status reports what the call is doing, e.g. waiting for a retry.
+func (r *Reporter) Status(message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.members != nil {
+		for m := range r.members {
+			m.Status(message)
+		}
+		return
+	}
+	r.progress++
+	r.send(0, message)
+}
+
+// Reader returns body counting the bytes read from it as progress. length
+// is the expected size of body, or -1 when unknown.
+func (r *Reporter) Reader(body io.Reader, length int64) io.Reader {
+	if r == nil {
+		return body
+	}
+	if r.members == nil {
+		return r.download(body, length)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downloading, r.remaining = true, length
+	for m := range r.members {
+		r.members[m] = m.download(nil, length)
+	}
+	return &relay{r: r, body: body}
+}
+
+func (r *Reporter) download(body io.Reader, length int64) *download {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := &download{r: r, body: body, start: r.progress}
+	if length >= 0 {
+		d.total = r.progress + float64(length)
+	}
+	return d
+}
+
+// send notifies the client unless nothing has progressed since the last
+// notification or the call has ended, which happens when the call gave up on
+// an upstream request it shares with other calls. Callers hold r.mu.
+func (r *Reporter) send(total float64, message string) {
+	if r.progress <= r.reported || r.ctx.Err() != nil {
+		return
+	}
+	params := map[string]any{
+		"progressToken": r.token,
+		"progress":      r.progress,
+		"message":       message,
+	}
+	if total > 0 {
+		params["total"] = max(total, r.progress)
+	}
+	r.reported, r.sent = r.progress, time.Now()
+	// The call goes on whether or not the client still listens
+	_ = r.srv.SendNotificationToClient(r.ctx, string(mcp.MethodNotificationProgress), params)
+}
+
+// download counts the bytes of a response body.
+type download struct {
+	r     *Reporter
+	body  io.Reader
+	start float64 // Progress when the download started
+	total float64 // Progress once the body is read, 0 when unknown
+}
+
+func (d *download) Read(p []byte) (int, error) {
+	n, err := d.body.Read(p)
+	d.count(n, err)
+	return n, err
+}
+
+// count adds the result of a read to the progress.
+func (d *download) count(n int, err error) {
+	r := d.r
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress += float64(n)
+	if time.Since(r.sent) >= interval || err == io.EOF {
+		received := r.progress - d.start
+		message := "Received " + size(received)
+		if d.total > 0 {
+			message += " of " + size(d.total-d.start)
+		}
+		r.send(d.total, message)
+	}
+}
+
+// relay counts the bytes of a response body for every member of a shared
+// Reporter.
+type relay struct {
+	r    *Reporter
+	body io.Reader
+}
+
+func (d *relay) Read(p []byte) (int, error) {
+	n, err := d.body.Read(p)
+	r := d.r
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.remaining >= 0 {
+		r.remaining = max(r.remaining-int64(n), 0)
+	}
+	if err != nil {
+		r.downloading = false
+	}
+	for _, m := range r.members {
+		if m != nil {
+			m.count(n, err)
+		}
+	}
+	return n, err
+}
+
+// size formats a byte count.
+func size(n float64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", n/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f kB", n/(1<<10))
+	}
+	return fmt.Sprintf("%.0f bytes", n)
+}
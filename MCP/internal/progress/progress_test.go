@@ -0,0 +1,147 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// session collects the notifications sent to it.
+type session struct {
+	ch chan mcp.JSONRPCNotification
+}
+
+func (s *session) Initialize()                                         {}
+func (s *session) Initialized() bool                                   { return true }
+func (s *session) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.ch }
+func (s *session) SessionID() string                                   { return "test" }
+
+// notifications returns the notifications sent so far.
+func (s *session) notifications() []map[string]any {
+	var got []map[string]any
+	for {
+		select {
+		case n := <-s.ch:
+			got = append(got, n.Params.AdditionalFields)
+		default:
+			return got
+		}
+	}
+}
+
+func newReporter() (*Reporter, *session) {
+	srv := server.NewMCPServer("test", "0")
+	s := &session{ch: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := srv.WithContext(context.Background(), s)
+	return &Reporter{ctx: ctx, srv: srv, token: "call-1", reported: -1}, s
+}
+
+// read reads body from r in chunks of size bytes.
+func read(t *testing.T, r io.Reader, size int) {
+	t.Helper()
+	buf := make([]byte, size)
+	for {
+		_, err := r.Read(buf)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDownloadThrottled(t *testing.T) {
+	r, s := newReporter()
+	body := strings.Repeat("x", 10_000)
+	read(t, r.Reader(strings.NewReader(body), int64(len(body))), 100)
+
+	// The first read is reported, the next 99 fall within the interval and
+	// the end of the body is always reported.
+	got := s.notifications()
+	if len(got) != 2 {
+		t.Fatalf("got %d notifications, want 2: %v", len(got), got)
+	}
+	if got[0]["progress"] != 100.0 || got[0]["total"] != 10_000.0 || got[0]["progressToken"] != mcp.ProgressToken("call-1") {
+		t.Errorf("first notification = %v", got[0])
+	}
+	if got[1]["progress"] != 10_000.0 || got[1]["message"] != "Received 9.8 kB of 9.8 kB" {
+		t.Errorf("last notification = %v", got[1])
+	}
+}
+
+func TestDownloadInterval(t *testing.T) {
+	r, s := newReporter()
+	d := r.Reader(strings.NewReader(strings.Repeat("x", 300)), -1)
+	buf := make([]byte, 100)
+	for range 3 {
+		// Pretend the interval has passed since the last notification
+		r.sent = time.Now().Add(-interval)
+		if _, err := d.Read(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := s.notifications()
+	if len(got) != 3 {
+		t.Fatalf("got %d notifications, want 3: %v", len(got), got)
+	}
+	for i, n := range got {
+		if want := float64(100 * (i + 1)); n["progress"] != want {
+			t.Errorf("notification %d progress = %v, want %v", i, n["progress"], want)
+		}
+		if _, ok := n["total"]; ok {
+			t.Errorf("notification %d has a total for a body of unknown length", i)
+		}
+	}
+
+	// EOF reports nothing new, since no bytes arrived after the last notification
+	if _, err := d.Read(buf); err != io.EOF {
+		t.Fatalf("Read = %v, want EOF", err)
+	}
+	if got := s.notifications(); len(got) != 0 {
+		t.Errorf("notifications without progress: %v", got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	r, s := newReporter()
+	r.Status("Waiting for the rate limit")
+	read(t, r.Reader(strings.NewReader("abc"), 3), 10)
+	r.Status("Retrying in 1s")
+
+	got := s.notifications()
+	want := []struct {
+		progress float64
+		message  string
+	}{
+		{1, "Waiting for the rate limit"},
+		{4, "Received 3 bytes of 3 bytes"},
+		{5, "Retrying in 1s"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d notifications, want %d: %v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i]["progress"] != w.progress || got[i]["message"] != w.message {
+			t.Errorf("notification %d = %v, want progress %v and %q", i, got[i], w.progress, w.message)
+		}
+	}
+}
+
+func TestNilReporter(t *testing.T) {
+	r := FromContext(context.Background())
+	if r != nil {
+		t.Fatal("FromContext returned a reporter for a call without one")
+	}
+	r.Status("ignored")
+	body := strings.NewReader("abc")
+	if got := r.Reader(body, 3); got != io.Reader(body) {
+		t.Errorf("Reader wrapped the body of a call without a reporter")
+	}
+}
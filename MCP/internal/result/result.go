@@ -0,0 +1,71 @@
+// Package result turns Finnhub responses into MCP tool results.
+package result
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ItemsKey is the field that carries array responses in structured content,
+// which MCP requires to be an object. The generated output schemas wrap
+// array definitions the same way.
+const ItemsKey = "items"
+
+// JSON returns a result with the response as pretty-printed text and, when
+// the body is valid JSON, as structured content. Numbers are kept as they
+// were sent so large IDs and timestamps do not lose precision. Bodies that
+// are not JSON are returned as plain text.
+func JSON(body []byte) *mcp.CallToolResult {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return mcp.NewToolResultText(string(body))
+	}
+
+	pretty, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultText(string(body))
+	}
+	return mcp.NewToolResultStructured(Structured(value), string(pretty))
+}
+
+// Structured wraps non-object values under ItemsKey.
+func Structured(value any) map[string]any {
+	if obj, ok := value.(map[string]any); ok {
+		return obj
+	}
+	return map[string]any{ItemsKey: value}
+}
+
+// Error turns an error response from the API into a tool error. Responses
+// denying access to the endpoint explain which plan the tool requires.
+func Error(ctx context.Context, cfg *config.APIConfig, tool string, access plan.Access, resp *upstream.Response) *mcp.CallToolResult {
+	if plan.Denied(resp.StatusCode, resp.Body) {
+		err := &plan.Error{Tool: tool, Access: access, Plan: config.ForContext(ctx, cfg).Plan}
+		return mcp.NewToolResultError(err.Error())
+	}
+	return mcp.NewToolResultError(resp.ErrorMessage())
+}
+
+// Value returns the structured content of a tool result, or its error text
+// as an error. Array responses stay wrapped under ItemsKey.
+func Value(res *mcp.CallToolResult) (map[string]any, error) {
+	if res.IsError {
+		for _, c := range res.Content {
+			if tc, ok := mcp.AsTextContent(c); ok {
+				return nil, errors.New(tc.Text)
+			}
+		}
+		return nil, errors.New("tool call failed")
+	}
+	structured, _ := res.StructuredContent.(map[string]any)
+	return structured, nil
+}
@@ -0,0 +1,60 @@
+package result
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		structured any // nil when the result has no structured content
+		text       string
+	}{
+		{
+			name:       "object",
+			body:       `{"c":1.5,"id":12345678901234567890}`,
+			structured: map[string]any{"c": json.Number("1.5"), "id": json.Number("12345678901234567890")},
+			text:       "{\n  \"c\": 1.5,\n  \"id\": 12345678901234567890\n}",
+		},
+		{
+			name:       "array",
+			body:       `["AAPL","MSFT"]`,
+			structured: map[string]any{ItemsKey: []any{"AAPL", "MSFT"}},
+			text:       "[\n  \"AAPL\",\n  \"MSFT\"\n]",
+		},
+		{
+			name:       "scalar",
+			body:       `42`,
+			structured: map[string]any{ItemsKey: json.Number("42")},
+			text:       "42",
+		},
+		{name: "not JSON", body: "symbol,price\nAAPL,1", text: "symbol,price\nAAPL,1"},
+		{name: "trailing data", body: `{} {}`, text: `{} {}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := JSON([]byte(tt.body))
+			if res.IsError {
+				t.Fatal("result is an error")
+			}
+			if !reflect.DeepEqual(res.StructuredContent, tt.structured) {
+				t.Errorf("structured = %#v, want %#v", res.StructuredContent, tt.structured)
+			}
+			if len(res.Content) != 1 {
+				t.Fatalf("got %d content blocks, want 1", len(res.Content))
+			}
+			text, ok := res.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatalf("content is %T, want mcp.TextContent", res.Content[0])
+			}
+			if text.Text != tt.text {
+				t.Errorf("text = %q, want %q", text.Text, tt.text)
+			}
+		})
+	}
+}
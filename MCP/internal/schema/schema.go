@@ -0,0 +1,279 @@
+// Package schema validates tool arguments against the JSON Schema fragments
+// used in tool definitions, so malformed input is rejected with a clear error
+// before anything is sent upstream.
+//
+// Only the keywords the tool definitions use are supported: type, enum,
+// minimum, maximum, format (date only), properties, required,
+// additionalProperties and items.
+package schema
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// dateLayout is the YYYY-MM-DD form Finnhub uses for date parameters.
+const dateLayout = "2006-01-02"
+
+// Property returns a PropertyOption that copies s into a tool property, so a
+// schema declared once can back both the tool definition and Validate.
+func Property(s map[string]any) mcp.PropertyOption {
+	return func(prop map[string]any) {
+		maps.Copy(prop, s)
+	}
+}
+
+// Integer narrows a number property to whole numbers. mcp-go only offers
+// WithNumber, which declares "number".
+func Integer() mcp.PropertyOption {
+	return func(prop map[string]any) {
+		prop["type"] = "integer"
+	}
+}
+
+// Format sets the format of a string property, e.g. "date".
+func Format(format string) mcp.PropertyOption {
+	return func(prop map[string]any) {
+		prop["format"] = format
+	}
+}
+
+// Checked wraps a tool handler so that calls whose arguments do not match the
+// tool's input schema are rejected before the handler runs. Unknown
+// arguments are rejected too, which catches misspelled parameter names.
+func Checked(tool mcp.Tool, handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	props := tool.InputSchema.Properties
+	if props == nil {
+		props = map[string]any{}
+	}
+	input := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"required":             tool.InputSchema.Required,
+		"additionalProperties": false,
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Arguments == nil {
+			request.Params.Arguments = map[string]any{}
+		}
+		if err := Validate(input, request.Params.Arguments); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+		}
+		return handler(ctx, request)
+	}
+}
+
+// Validate checks value against s and returns an error naming the first
+// offending field. A field set to null counts as absent, as it does for
+// params.Query: it satisfies no required field and is accepted for any
+// optional one.
+func Validate(s map[string]any, value any) error {
+	return validate(s, value, "")
+}
+
+func validate(s map[string]any, value any, path string) error {
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		return fmt.Errorf("%sexpected %s, got %s", prefix(path), typeString(t), typeOf(value))
+	}
+	if enum, ok := s["enum"]; ok && !slices.ContainsFunc(toSlice(enum), func(e any) bool { return equal(e, value) }) {
+		return fmt.Errorf("%smust be one of %s", prefix(path), joinValues(toSlice(enum)))
+	}
+	if n, ok := value.(float64); ok {
+		if minimum, ok := toFloat(s["minimum"]); ok && n < minimum {
+			return fmt.Errorf("%smust be >= %v", prefix(path), minimum)
+		}
+		if maximum, ok := toFloat(s["maximum"]); ok && n > maximum {
+			return fmt.Errorf("%smust be <= %v", prefix(path), maximum)
+		}
+	}
+
+	if str, ok := value.(string); ok && s["format"] == "date" {
+		if _, err := time.Parse(dateLayout, str); err != nil {
+			return fmt.Errorf("%sexpected a date in YYYY-MM-DD form, got %q", prefix(path), str)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(s, v, path)
+	case []any:
+		items, ok := s["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range v {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateObject(s map[string]any, obj map[string]any, path string) error {
+	props, _ := s["properties"].(map[string]any)
+	for _, name := range toSlice(s["required"]) {
+		if v, ok := obj[fmt.Sprint(name)]; !ok || v == nil {
+			return fmt.Errorf("%s: required field is missing", join(path, fmt.Sprint(name)))
+		}
+	}
+
+	names := slices.Sorted(maps.Keys(obj))
+	for _, name := range names {
+		propSchema, known := props[name].(map[string]any)
+		if !known {
+			switch extra := s["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unknown field (allowed: %s)", join(path, name), strings.Join(slices.Sorted(maps.Keys(props)), ", "))
+				}
+			case map[string]any:
+				propSchema = extra
+			}
+		}
+		if propSchema == nil || obj[name] == nil {
+			continue
+		}
+		if err := validate(propSchema, obj[name], join(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesType(t any, value any) bool {
+	for _, name := range toSlice(t) {
+		switch name {
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeString(t any) string {
+	names := make([]string, 0)
+	for _, name := range toSlice(t) {
+		names = append(names, fmt.Sprint(name))
+	}
+	return strings.Join(names, " or ")
+}
+
+// toSlice normalises the []string and []any forms used for enum, required
+// and type lists, and wraps a single value.
+func toSlice(v any) []any {
+	switch s := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return s
+	case []string:
+		out := make([]any, len(s))
+		for i, e := range s {
+			out[i] = e
+		}
+		return out
+	}
+	return []any{v}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func equal(a, b any) bool {
+	switch b.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		return af == bf
+	}
+	return a == b
+}
+
+func joinValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func prefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
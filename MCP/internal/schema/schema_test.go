@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestValidate(t *testing.T) {
+	s := map[string]any{
+		"type":                 "object",
+		"required":             []string{"symbol"},
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"symbol":     map[string]any{"type": "string"},
+			"resolution": map[string]any{"type": "string", "enum": []any{"1", "5", "D"}},
+			"limit":      map[string]any{"type": "integer", "minimum": 1, "maximum": 100},
+			"ratio":      map[string]any{"type": "number"},
+			"from":       map[string]any{"type": "string", "format": "date"},
+			"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"filter": map[string]any{
+				"type":                 "object",
+				"required":             []any{"field"},
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"field": map[string]any{"type": "string", "enum": []string{"pe", "eps"}},
+					"range": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"gte": map[string]any{"type": "number"},
+						},
+					},
+				},
+			},
+			"extra": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "integer"},
+			},
+			"free": map[string]any{"type": "object"},
+		},
+	}
+	tests := []struct {
+		name    string
+		value   map[string]any
+		wantErr string // "" for valid
+	}{
+		{"minimal", map[string]any{"symbol": "AAPL"}, ""},
+		{"all fields", map[string]any{
+			"symbol": "AAPL", "resolution": "D", "limit": 10.0, "ratio": 0.5, "from": "2024-01-31",
+			"tags": []any{"a", "b"}, "filter": map[string]any{"field": "pe", "range": map[string]any{"gte": 1.5}},
+			"extra": map[string]any{"n": 3.0}, "free": map[string]any{"anything": "goes"},
+		}, ""},
+
+		// Types
+		{"wrong type", map[string]any{"symbol": 1.0}, "symbol: expected string, got integer"},
+		{"fraction for integer", map[string]any{"symbol": "AAPL", "limit": 2.5}, "limit: expected integer, got number"},
+		{"integer for number", map[string]any{"symbol": "AAPL", "ratio": 2.0}, ""},
+		{"array items", map[string]any{"symbol": "AAPL", "tags": []any{"a", 1.0}}, "tags[1]: expected string, got integer"},
+
+		// Required fields and null
+		{"missing required", map[string]any{}, "symbol: required field is missing"},
+		{"null required", map[string]any{"symbol": nil}, "symbol: required field is missing"},
+		{"null optional", map[string]any{"symbol": "AAPL", "resolution": nil, "limit": nil, "from": nil, "filter": nil}, ""},
+		{"null nested optional", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": "pe", "range": nil}}, ""},
+		{"null nested required", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": nil}}, "filter.field: required field is missing"},
+		{"null unknown", map[string]any{"symbol": "AAPL", "limt": nil}, "limt: unknown field"},
+
+		// Enums and bounds
+		{"enum", map[string]any{"symbol": "AAPL", "resolution": "W"}, "resolution: must be one of 1, 5, D"},
+		{"enum number for string", map[string]any{"symbol": "AAPL", "resolution": 5.0}, "resolution: expected string, got integer"},
+		{"nested enum", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": "roe"}}, "filter.field: must be one of"},
+		{"below minimum", map[string]any{"symbol": "AAPL", "limit": 0.0}, "limit: must be >= 1"},
+		{"above maximum", map[string]any{"symbol": "AAPL", "limit": 101.0}, "limit: must be <= 100"},
+		{"date", map[string]any{"symbol": "AAPL", "from": "2024-02-30"}, "from: expected a date in YYYY-MM-DD form"},
+		{"timestamp for date", map[string]any{"symbol": "AAPL", "from": "2024-01-31T00:00:00Z"}, "from: expected a date"},
+
+		// Nested objects and additionalProperties
+		{"unknown field", map[string]any{"symbol": "AAPL", "limt": 10.0}, "limt: unknown field (allowed: extra, filter, free, from, limit, ratio, resolution, symbol, tags)"},
+		{"nested unknown field", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": "pe", "op": "gt"}}, "filter.op: unknown field (allowed: field, range)"},
+		{"nested missing required", map[string]any{"symbol": "AAPL", "filter": map[string]any{}}, "filter.field: required field is missing"},
+		{"deeply nested type", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": "pe", "range": map[string]any{"gte": "1"}}}, "filter.range.gte: expected number, got string"},
+		{"additionalProperties default", map[string]any{"symbol": "AAPL", "filter": map[string]any{"field": "pe", "range": map[string]any{"lte": "x"}}}, ""},
+		{"additionalProperties schema", map[string]any{"symbol": "AAPL", "extra": map[string]any{"n": "3"}}, "extra.n: expected integer, got string"},
+		{"object for string", map[string]any{"symbol": map[string]any{}}, "symbol: expected string, got object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(s, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChecked(t *testing.T) {
+	tool := mcp.NewTool("get_test",
+		mcp.WithString("symbol", mcp.Required()),
+		mcp.WithNumber("from", Integer()),
+		mcp.WithString("resolution", mcp.Enum("D", "W")),
+	)
+	called := 0
+	handler := Checked(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"symbol": "AAPL", "from": 1700000000.0}, ""},
+		{"null optional", map[string]any{"symbol": "AAPL", "from": nil, "resolution": nil}, ""},
+		{"no arguments", nil, "symbol: required field is missing"},
+		{"unknown argument", map[string]any{"symbol": "AAPL", "to": 1.0}, "to: unknown field"},
+		{"wrong type", map[string]any{"symbol": "AAPL", "from": "yesterday"}, "from: expected integer, got string"},
+		{"enum", map[string]any{"symbol": "AAPL", "resolution": "M"}, "resolution: must be one of D, W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = 0
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tool.Name
+			if tt.args != nil {
+				request.Params.Arguments = tt.args
+			}
+			res, err := handler(context.Background(), request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr == "" {
+				if res.IsError || called != 1 {
+					t.Errorf("valid call rejected: %+v", res.Content)
+				}
+				return
+			}
+			if !res.IsError || called != 0 {
+				t.Fatalf("invalid call reached the handler")
+			}
+			text := res.Content[0].(mcp.TextContent).Text
+			if !strings.HasPrefix(text, "Invalid arguments: ") || !strings.Contains(text, tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", text, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Package session keeps the state of HTTP mode MCP sessions. A session is
+// created by an initialize request, carries the API configuration taken from
+// that request's headers, and stays usable through its Mcp-Session-Id until
+// the client deletes it or it has been idle for too long.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/access"
+	"github.com/finnhub-api/mcp-server/internal/auth"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const idPrefix = "mcp-session-"
+
+// Store tracks live sessions. It implements server.SessionIdManager, so the
+// streamable HTTP server only accepts session IDs the store handed out.
+type Store struct {
+	idle time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*entry
+	onEvict  func(id string)
+	stop     chan struct{}
+}
+
+type entry struct {
+	cfg      *config.APIConfig // Nil until bound by HTTPContext
+	owner    string            // access.Principal of the client that initialized the session
+	lastSeen time.Time
+	active   int // Requests currently being served, e.g. an open GET stream
+}
+
+var _ server.SessionIdManager = (*Store)(nil)
+
+// NewStore returns a store whose sessions are evicted after idle without
+// requests. An idle of 0 keeps sessions until they are deleted.
+func NewStore(idle time.Duration) *Store {
+	s := &Store{
+		idle:     idle,
+		sessions: map[string]*entry{},
+		stop:     make(chan struct{}),
+	}
+	if idle > 0 {
+		go s.evictIdle()
+	}
+	return s
+}
+
+// Close stops the eviction loop.
+func (s *Store) Close() {
+	close(s.stop)
+}
+
+// Generate creates a session for an initialize request. The session has no
+// configuration, and cannot be used, until HTTPContext binds one.
+func (s *Store) Generate() string {
+	id := idPrefix + rand.Text()
+	s.mu.Lock()
+	s.sessions[id] = &entry{lastSeen: time.Now()}
+	s.mu.Unlock()
+	return id
+}
+
+// HTTPContext is a server.HTTPContextFunc that binds a session created by
+// Generate to the API configuration the /mcp handler validated and attached
+// to the initialize request with config.NewContext, and to the principal
+// that sent the request. Sessions that already have a configuration keep it,
+// and a request without one binds nothing.
+func (s *Store) HTTPContext(ctx context.Context, r *http.Request) context.Context {
+	session := server.ClientSessionFromContext(ctx)
+	cfg, ok := config.FromContext(r.Context())
+	if session == nil || !ok {
+		return ctx
+	}
+	id := session.SessionID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.sessions[id]; ok && e.cfg == nil {
+		e.cfg = cfg
+		e.owner = access.Principal(r.Context())
+		slog.Info("Session started", "session", id, "base_url", auth.RedactURL(cfg.BaseURL))
+	}
+	return ctx
+}
+
+// Validate reports unknown, deleted and evicted sessions as terminated, which
+// tells the client to initialize a new one.
+func (s *Store) Validate(id string) (isTerminated bool, err error) {
+	if id == "" {
+		return false, errors.New("missing session id")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.sessions[id]
+	if !ok {
+		return true, nil
+	}
+	e.lastSeen = time.Now()
+	return false, nil
+}
+
+// Terminate deletes a session at the client's request.
+func (s *Store) Terminate(id string) (isNotAllowed bool, err error) {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return false, nil
+}
+
+// Acquire marks a session as in use for the duration of an HTTP request so
+// it is not evicted while, for example, a notification stream is open, and
+// returns the session's API configuration. It returns false for unknown
+// sessions, for sessions that were never bound to a configuration and for
+// sessions that belong to a different principal.
+func (s *Store) Acquire(id, principal string) (cfg *config.APIConfig, release func(), ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.sessions[id]
+	if !ok || e.cfg == nil || e.owner != principal {
+		return nil, nil, false
+	}
+	e.active++
+	e.lastSeen = time.Now()
+	return e.cfg, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e.active--
+		e.lastSeen = time.Now()
+	}, true
+}
+
+// OnEvict registers f to be called with the ID of every session evicted for
+// being idle, so state kept elsewhere for the session can be released.
+func (s *Store) OnEvict(f func(id string)) {
+	s.mu.Lock()
+	s.onEvict = f
+	s.mu.Unlock()
+}
+
+// Len returns the number of live sessions.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+func (s *Store) evictIdle() {
+	ticker := time.NewTicker(min(s.idle, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.evict(now)
+		}
+	}
+}
+
+// evict deletes the sessions that are not in use and were last seen more
+// than s.idle before now, then passes their IDs to the OnEvict function
+// outside the lock.
+func (s *Store) evict(now time.Time) {
+	var evicted []string
+	s.mu.Lock()
+	for id, e := range s.sessions {
+		if e.active == 0 && now.Sub(e.lastSeen) > s.idle {
+			delete(s.sessions, id)
+			evicted = append(evicted, id)
+			slog.Info("Session evicted", "session", id, "idle", s.idle)
+		}
+	}
+	onEvict := s.onEvict
+	s.mu.Unlock()
+	if onEvict != nil {
+		for _, id := range evicted {
+			onEvict(id)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/access"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// clientSession is the MCP session the streamable HTTP server creates for a
+// request.
+type clientSession string
+
+func (s clientSession) Initialize()                                         {}
+func (s clientSession) Initialized() bool                                   { return true }
+func (s clientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s clientSession) SessionID() string                                   { return string(s) }
+
+var guard = access.New(&config.AccessConfig{Tokens: []string{"alice-token", "bob-token"}})
+
+// authenticated returns a request to /mcp that passed the access guard with
+// token.
+func authenticated(token string) *http.Request {
+	var out *http.Request
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	guard.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) { out = r })).ServeHTTP(httptest.NewRecorder(), r)
+	return out
+}
+
+func principal(token string) string {
+	return access.Principal(authenticated(token).Context())
+}
+
+// start creates a session the way an initialize request from the client
+// holding token does: the /mcp handler attaches cfg to the request, the
+// server generates an ID and then calls HTTPContext.
+func start(s *Store, cfg *config.APIConfig, token string) string {
+	r := authenticated(token)
+	if cfg != nil {
+		r = r.WithContext(config.NewContext(r.Context(), cfg))
+	}
+	id := s.Generate()
+	ctx := server.NewMCPServer("test", "1.0").WithContext(r.Context(), clientSession(id))
+	s.HTTPContext(ctx, r)
+	return id
+}
+
+func TestGenerate(t *testing.T) {
+	s := NewStore(0)
+	defer s.Close()
+	cfg := &config.APIConfig{BaseURL: "https://finnhub.io/api/v1", APIKey: "client-key"}
+	id := start(s, cfg, "alice-token")
+	if !strings.HasPrefix(id, idPrefix) || id == start(s, cfg, "alice-token") {
+		t.Errorf("unexpected session ID %q", id)
+	}
+
+	got, release, ok := s.Acquire(id, principal("alice-token"))
+	if !ok || got != cfg {
+		t.Fatalf("Acquire = %v, %v, want the session's configuration", got, ok)
+	}
+	release()
+
+	// Later requests for the session cannot replace its configuration
+	r := authenticated("alice-token")
+	other := &config.APIConfig{BaseURL: "http://evil.example", APIKey: "other-key"}
+	ctx := server.NewMCPServer("test", "1.0").WithContext(context.Background(), clientSession(id))
+	s.HTTPContext(ctx, r.WithContext(config.NewContext(r.Context(), other)))
+	if got, release, _ := s.Acquire(id, principal("alice-token")); got != cfg {
+		t.Errorf("session configuration replaced by %+v", got)
+	} else {
+		release()
+	}
+}
+
+func TestOwner(t *testing.T) {
+	s := NewStore(0)
+	defer s.Close()
+	id := start(s, &config.APIConfig{APIKey: "alice-key"}, "alice-token")
+	if principal("alice-token") == "" || principal("alice-token") == principal("bob-token") {
+		t.Fatal("tokens do not authenticate as distinct principals")
+	}
+
+	// Another client that learns the session ID cannot use it
+	if cfg, _, ok := s.Acquire(id, principal("bob-token")); ok {
+		t.Errorf("session acquired by another principal with %+v", cfg)
+	}
+	if cfg, _, ok := s.Acquire(id, ""); ok {
+		t.Errorf("session acquired without authentication with %+v", cfg)
+	}
+	cfg, release, ok := s.Acquire(id, principal("alice-token"))
+	if !ok || cfg.APIKey != "alice-key" {
+		t.Fatalf("owner cannot acquire the session: %+v, %v", cfg, ok)
+	}
+	release()
+}
+
+func TestUnboundSession(t *testing.T) {
+	s := NewStore(0)
+	defer s.Close()
+	// A session generated for a request the /mcp handler did not validate
+	// is never usable
+	id := start(s, nil, "alice-token")
+	if cfg, _, ok := s.Acquire(id, principal("alice-token")); ok {
+		t.Errorf("unbound session acquired with %+v", cfg)
+	}
+	if _, _, ok := s.Acquire(idPrefix+"unknown", principal("alice-token")); ok {
+		t.Error("unknown session acquired")
+	}
+}
+
+func TestValidateAndTerminate(t *testing.T) {
+	s := NewStore(0)
+	defer s.Close()
+	id := start(s, &config.APIConfig{}, "alice-token")
+
+	if terminated, err := s.Validate(id); terminated || err != nil {
+		t.Errorf("Validate(live) = %v, %v", terminated, err)
+	}
+	if _, err := s.Validate(""); err == nil {
+		t.Error("Validate accepted an empty ID")
+	}
+	if notAllowed, err := s.Terminate(id); notAllowed || err != nil {
+		t.Errorf("Terminate = %v, %v", notAllowed, err)
+	}
+	if terminated, _ := s.Validate(id); !terminated {
+		t.Error("deleted session is not terminated")
+	}
+	if _, _, ok := s.Acquire(id, principal("alice-token")); ok {
+		t.Error("deleted session acquired")
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len = %d after Terminate", s.Len())
+	}
+}
+
+func TestEvict(t *testing.T) {
+	s := NewStore(time.Hour)
+	defer s.Close()
+	idle := start(s, &config.APIConfig{}, "alice-token")
+	busy := start(s, &config.APIConfig{}, "alice-token")
+	_, release, _ := s.Acquire(busy, principal("alice-token"))
+	var evicted []string
+	s.OnEvict(func(id string) {
+		// Called outside the lock, so the store can be used from here
+		s.Len()
+		evicted = append(evicted, id)
+	})
+
+	s.evict(time.Now().Add(30 * time.Minute))
+	if s.Len() != 2 {
+		t.Fatalf("Len = %d, sessions evicted before the idle timeout", s.Len())
+	}
+	s.evict(time.Now().Add(2 * time.Hour))
+	if terminated, _ := s.Validate(idle); !terminated {
+		t.Error("idle session not evicted")
+	}
+	if terminated, _ := s.Validate(busy); terminated {
+		t.Error("session in use evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != idle {
+		t.Errorf("OnEvict called with %v, want [%s]", evicted, idle)
+	}
+
+	release()
+	s.evict(time.Now().Add(2 * time.Hour))
+	if s.Len() != 0 || len(evicted) != 2 || evicted[1] != busy {
+		t.Errorf("Len = %d, OnEvict called with %v after the busy session was released", s.Len(), evicted)
+	}
+}
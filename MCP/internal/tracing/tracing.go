@@ -0,0 +1,103 @@
+// Package tracing records OpenTelemetry spans for tool calls and the
+// upstream requests they make, exported over OTLP/HTTP. Tracing is off unless
+// an OTLP endpoint is configured with the standard OTEL_EXPORTER_OTLP_*
+// environment variables, in which case spans cost next to nothing.
+//
+// Every tool call gets a span, continuing the trace of a W3C traceparent
+// header sent with the /mcp request. Below it, each upstream request gets a
+// span carrying the endpoint path, cache and coalescing outcome and retry
+// count, with one client span per HTTP attempt. Spans never carry query
+// strings or headers, which may hold credentials.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName names the server in traces unless OTEL_SERVICE_NAME is set.
+const serviceName = "finnhub-mcp-server"
+
+// Attribute keys of the spans, from the OpenTelemetry conventions for MCP and
+// HTTP where they have one.
+const (
+	MethodKey     = attribute.Key("mcp.method.name")
+	ToolKey       = attribute.Key("gen_ai.tool.name")
+	PathKey       = attribute.Key("url.path")
+	HTTPMethodKey = attribute.Key("http.request.method")
+	ServerKey     = attribute.Key("server.address")
+	StatusKey     = attribute.Key("http.response.status_code")
+	ResendKey     = attribute.Key("http.request.resend_count")
+	RetriesKey    = attribute.Key("finnhub.retry_count")
+	CacheHitKey   = attribute.Key("finnhub.cache_hit")
+	CoalescedKey  = attribute.Key("finnhub.coalesced")
+)
+
+// Tracer returns the tracer of the server's spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/finnhub-api/mcp-server")
+}
+
+// Setup installs the OTLP exporter when an endpoint is configured and returns
+// a function flushing the spans still buffered, for shutdown. W3C trace
+// context is propagated either way.
+func Setup(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	shutdown = func(context.Context) error { return nil }
+	if os.Getenv("OTEL_SDK_DISABLED") == "true" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return shutdown, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res := resource.Default()
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		res, err = resource.Merge(res, resource.NewSchemaless(attribute.String("service.name", serviceName)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Middleware records a span for every tool call. The trace continues the one
+// named by a traceparent header of the HTTP request carrying the call.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Header != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(request.Header))
+		}
+		tool := request.Params.Name
+		ctx, span := Tracer().Start(ctx, string(mcp.MethodToolsCall)+" "+tool,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(MethodKey.String(string(mcp.MethodToolsCall)), ToolKey.String(tool)))
+		defer span.End()
+
+		res, err := next(ctx, request)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case res != nil && res.IsError:
+			span.SetStatus(codes.Error, "tool returned an error")
+		}
+		return res, err
+	}
+}
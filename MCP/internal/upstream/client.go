@@ -0,0 +1,311 @@
+// Package upstream is the request path every tool uses to call the Finnhub
+// API. It owns the pooled HTTP transport, timeouts and authentication so the
+// generated handlers only have to describe the request they want to make.
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/auth"
+	"github.com/finnhub-api/mcp-server/internal/cache"
+	"github.com/finnhub-api/mcp-server/internal/metrics"
+	"github.com/finnhub-api/mcp-server/internal/progress"
+	"github.com/finnhub-api/mcp-server/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxResponseBytes bounds the response body read into memory. The largest
+// Finnhub responses, such as tick data, stay well below it.
+const maxResponseBytes = 64 << 20
+
+// Request describes a single call to the Finnhub API.
+type Request struct {
+	Tool   string // Name of the tool making the request, e.g. get_quote
+	Method string
+	Path   string     // Endpoint path relative to the configured base URL, e.g. "/stock/candle"
+	Query  url.Values // Query parameters, encoded with proper escaping
+	Body   []byte     // JSON request body, sent with Content-Type: application/json
+	// Idempotent marks a non-GET request as safe to retry. GET requests are
+	// always retried on transient failures.
+	Idempotent bool
+}
+
+// url resolves the request against cfg.BaseURL.
+func (r Request) url(cfg *config.APIConfig) string {
+	u := strings.TrimRight(cfg.BaseURL, "/") + r.Path
+	if len(r.Query) > 0 {
+		// Encode spaces as %20 rather than "+", which not every upstream
+		// decodes as a space. Literal plus signs are already escaped as %2B.
+		u += "?" + strings.ReplaceAll(r.Query.Encode(), "+", "%20")
+	}
+	return u
+}
+
+// Response is a fully read upstream response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Attempts   int  // Number of attempts made, including the one that produced this response
+	Cached     bool // Served from the response cache without calling the API
+	Shared     bool // Answered by a request a concurrent call had already sent
+}
+
+// ErrorMessage describes a failed response for the tool result.
+func (r *Response) ErrorMessage() string {
+	return fmt.Sprintf("API error after %s: %s", attemptsString(r.Attempts), r.Body)
+}
+
+type transportKey struct {
+	proxyURL     string
+	maxIdleConns int
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[transportKey]*http.Transport{}
+)
+
+// transportFor returns the shared transport for cfg's connection settings.
+// Transports are cached for the life of the process so keep-alive
+// connections are reused across tool calls and across HTTP-mode requests,
+// which each carry their own APIConfig.
+func transportFor(cfg *config.APIConfig) (*http.Transport, error) {
+	key := transportKey{proxyURL: cfg.ProxyURL, maxIdleConns: cfg.MaxIdleConns}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[key]; ok {
+		return t, nil
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = 100
+	}
+
+	t := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdle,
+		MaxIdleConnsPerHost:   maxIdle,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	transports[key] = t
+	return t, nil
+}
+
+// Do sends r to the Finnhub API using the credentials, quotas and timeouts in
+// cfg. The whole call, including time spent queued behind the rate limiter and
+// between retries, is bounded by cfg.CallTimeout and each attempt by
+// cfg.RequestTimeout; cancelling ctx aborts the in-flight request.
+//
+// Network errors and 429/502/503/504 responses are retried up to
+// cfg.RetryMaxAttempts times for GET requests and requests marked Idempotent.
+// The returned Response or error reports how many attempts were made.
+//
+// Identical concurrent requests made with the same credentials share one
+// upstream request (see coalesce).
+//
+// Successful responses of tools with a cache TTL (see config.CacheTTL) are
+// cached and served without calling the API until they expire, unless ctx
+// bypasses the cache (see cache.WithBypass).
+//
+// In HTTP mode the tools are shared by every session, so the session's
+// configuration carried by ctx (see config.NewContext) takes precedence.
+func Do(ctx context.Context, cfg *config.APIConfig, r Request) (*Response, error) {
+	cfg = config.ForContext(ctx, cfg)
+	ctx, span := tracing.Tracer().Start(ctx, "finnhub "+r.Path,
+		trace.WithAttributes(tracing.ToolKey.String(r.Tool), tracing.PathKey.String(r.Path)))
+	defer span.End()
+
+	resp, err := cached(ctx, cfg, r)
+	if err != nil {
+		span.SetStatus(codes.Error, failure(nil, err))
+		return nil, err
+	}
+	span.SetAttributes(tracing.StatusKey.Int(resp.StatusCode), tracing.RetriesKey.Int(max(resp.Attempts-1, 0)),
+		tracing.CacheHitKey.Bool(resp.Cached), tracing.CoalescedKey.Bool(resp.Shared))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// cached serves r from the response cache, or fetches and caches it.
+func cached(ctx context.Context, cfg *config.APIConfig, r Request) (*Response, error) {
+	ttl := cfg.CacheTTL(r.Tool)
+	if ttl <= 0 {
+		return coalesce(ctx, cfg, r)
+	}
+	// Sessions share the server's cache settings, and main opens that
+	// store at startup, so a failure here only skips the cache
+	store, err := cache.For(cfg)
+	if err != nil || store == nil {
+		return coalesce(ctx, cfg, r)
+	}
+	// Credentials are added by the transport, so they are not part of the
+	// URL; responses are only shared between callers with the same ones
+	key := cache.Key(cfg.CredentialKey(), r.Method, r.url(cfg), r.Body)
+	if !cache.Bypassed(ctx) {
+		e, ok := store.Get(key)
+		metrics.CacheLookup(r.Tool, ok)
+		if ok {
+			return &Response{StatusCode: e.StatusCode, Header: e.Header, Body: e.Body, Cached: true}, nil
+		}
+	}
+	resp, err := coalesce(ctx, cfg, r)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		store.Set(key, &cache.Entry{StatusCode: resp.StatusCode, Header: resp.Header, Body: resp.Body, Expires: time.Now().Add(ttl)})
+	}
+	return resp, err
+}
+
+// fetch calls the API, retrying transient failures.
+func fetch(ctx context.Context, cfg *config.APIConfig, r Request) (*Response, error) {
+	if cfg.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.CallTimeout)
+		defer cancel()
+	}
+
+	maxAttempts := 1
+	if r.Method == http.MethodGet || r.Idempotent {
+		maxAttempts = max(cfg.RetryMaxAttempts, 1)
+	}
+
+	limiter := limiterFor(cfg)
+	for attempt := 1; ; attempt++ {
+		// Queue behind other calls made with the same key instead of letting
+		// Finnhub reject the burst with 429s.
+		wait, err := limiter.wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("after %s: %w", attemptsString(attempt-1), err)
+		}
+		metrics.RateLimited(wait)
+
+		resp, err := attemptSpan(ctx, cfg, r, attempt)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+		retry := attempt < maxAttempts && retryable(ctx, resp, err)
+		if retry {
+			metrics.Retried(r.Tool)
+			delay := backoff(cfg, attempt, resp)
+			slog.InfoContext(ctx, "Retrying upstream request", "tool", r.Tool, "path", r.Path,
+				"attempt", attempt, "reason", failure(resp, err), "delay", delay)
+			progress.FromContext(ctx).Status(fmt.Sprintf("Attempt %d of %d failed (%s); retrying in %s",
+				attempt, maxAttempts, failure(resp, err), delay.Round(time.Millisecond)))
+			retry = sleep(ctx, delay)
+		}
+		if !retry {
+			if err != nil {
+				return nil, fmt.Errorf("after %s: %w", attemptsString(attempt), err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// attemptSpan performs a single attempt in a client span of its own.
+func attemptSpan(ctx context.Context, cfg *config.APIConfig, r Request, attempt int) (*Response, error) {
+	attrs := []attribute.KeyValue{tracing.HTTPMethodKey.String(r.Method), tracing.PathKey.String(r.Path)}
+	if u, err := url.Parse(cfg.BaseURL); err == nil {
+		attrs = append(attrs, tracing.ServerKey.String(u.Hostname()))
+	}
+	if attempt > 1 {
+		attrs = append(attrs, tracing.ResendKey.Int(attempt-1))
+	}
+	ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+r.Path,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp, err := send(ctx, cfg, r)
+	if err != nil {
+		// Not err itself, whose URL carries the query
+		span.SetStatus(codes.Error, failure(nil, err))
+		return nil, err
+	}
+	span.SetAttributes(tracing.StatusKey.Int(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// send performs a single attempt.
+func send(ctx context.Context, cfg *config.APIConfig, r Request) (*Response, error) {
+	if cfg.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.RequestTimeout)
+		defer cancel()
+	}
+
+	transport, err := transportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.url(cfg), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Attempts without a complete response are counted as errors
+	code := 0
+	finished := metrics.UpstreamStarted(r.Tool)
+	defer func() { finished(code) }()
+
+	client := &http.Client{Transport: &auth.Transport{Config: cfg, Base: transport}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	counted := progress.FromContext(ctx).Reader(resp.Body, resp.ContentLength)
+	respBody, err := io.ReadAll(io.LimitReader(counted, maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(respBody) > maxResponseBytes {
+		return nil, fmt.Errorf("response larger than %d MB; request a smaller range of data", maxResponseBytes>>20)
+	}
+	code = resp.StatusCode
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}, nil
+}
@@ -0,0 +1,50 @@
+package upstream
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/finnhub-api/mcp-server/config"
+)
+
+// zeros is an endless reader of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestDoResponseSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{"at the limit", maxResponseBytes, false},
+		{"too large", maxResponseBytes + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(w, io.LimitReader(zeros{}, tt.size))
+			}))
+			defer srv.Close()
+
+			resp, err := Do(context.Background(), &config.APIConfig{BaseURL: srv.URL}, Request{Method: http.MethodGet, Path: "/"})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "response larger than 64 MB") {
+					t.Errorf("error = %v, want the response to be rejected as too large", err)
+				}
+				return
+			}
+			if err != nil || int64(len(resp.Body)) != tt.size {
+				t.Errorf("Do = %d bytes, %v, want %d bytes", len(resp.Body), err, tt.size)
+			}
+		})
+	}
+}
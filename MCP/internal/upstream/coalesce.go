@@ -0,0 +1,97 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/cache"
+	"github.com/finnhub-api/mcp-server/internal/progress"
+
+	"github.com/finnhub-api/mcp-server/internal/metrics"
+)
+
+// flight is an upstream request shared by every caller that asked for it
+// while it was in progress.
+type flight struct {
+	done     chan struct{} // Closed once resp and err are set
+	resp     *Response
+	err      error
+	waiters  int                // Callers still waiting, guarded by flightsMu
+	cancel   context.CancelFunc // Aborts the request once no caller waits
+	progress *progress.Reporter // Reports to the progress reporter of every caller
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = map[string]*flight{}
+)
+
+// coalesce fetches r, sharing the request with concurrent callers that ask
+// for the same request with the same credentials. The request runs on its own
+// context, so a caller that gives up returns at once without failing the
+// others; it is aborted when every caller has given up. Only requests that
+// are safe to retry are shared.
+func coalesce(ctx context.Context, cfg *config.APIConfig, r Request) (*Response, error) {
+	if r.Method != http.MethodGet && !r.Idempotent {
+		return fetch(ctx, cfg, r)
+	}
+	key := cache.Key(cfg.CredentialKey(), r.Method, r.url(cfg), r.Body)
+
+	flightsMu.Lock()
+	f, shared := flights[key]
+	if !shared {
+		// The request keeps the values of the first caller's context, such
+		// as its session configuration and trace, and reports its progress
+		// to every caller. Its attempt spans are therefore only part of the
+		// first caller's trace; the others get a request span marked
+		// coalesced
+		reporter := progress.Shared()
+		fctx, cancel := context.WithCancel(progress.NewContext(context.WithoutCancel(ctx), reporter))
+		f = &flight{done: make(chan struct{}), cancel: cancel, progress: reporter}
+		flights[key] = f
+		f.progress.Add(progress.FromContext(ctx))
+		go func() {
+			defer cancel()
+			f.resp, f.err = fetch(fctx, cfg, r)
+			flightsMu.Lock()
+			if flights[key] == f {
+				delete(flights, key)
+			}
+			flightsMu.Unlock()
+			close(f.done)
+		}()
+	} else {
+		f.progress.Add(progress.FromContext(ctx))
+	}
+	f.waiters++
+	flightsMu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		// Callers get their own copy; the body is shared and read-only
+		resp := *f.resp
+		resp.Shared = shared
+		if shared {
+			metrics.Coalesced(r.Tool)
+		}
+		return &resp, nil
+	case <-ctx.Done():
+		flightsMu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Later callers start a new request instead of joining one
+			// that is being aborted
+			if flights[key] == f {
+				delete(flights, key)
+			}
+			f.cancel()
+		}
+		flightsMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
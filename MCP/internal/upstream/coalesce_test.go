@@ -0,0 +1,280 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/cache"
+	"github.com/finnhub-api/mcp-server/internal/progress"
+	"github.com/finnhub-api/mcp-server/internal/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slowServer answers every request with body once release is closed, and
+// reports requests aborted by the client on aborted.
+type slowServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	release  chan struct{}
+	aborted  chan struct{}
+}
+
+func newSlowServer(t *testing.T, body string) *slowServer {
+	s := &slowServer{release: make(chan struct{}), aborted: make(chan struct{}, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		select {
+		case <-s.release:
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Write([]byte(body))
+		case <-r.Context().Done():
+			s.aborted <- struct{}{}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *slowServer) config(t *testing.T) *config.APIConfig {
+	return &config.APIConfig{BaseURL: s.URL, APIKey: t.Name(), RetryMaxAttempts: 1}
+}
+
+// waitForWaiters waits until n callers share the request for path.
+func waitForWaiters(t *testing.T, cfg *config.APIConfig, path string, n int) {
+	t.Helper()
+	key := cache.Key(cfg.CredentialKey(), http.MethodGet, Request{Path: path}.url(cfg), nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		flightsMu.Lock()
+		f := flights[key]
+		waiters := 0
+		if f != nil {
+			waiters = f.waiters
+		}
+		flightsMu.Unlock()
+		if waiters == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers share the request, want %d", waiters, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type result struct {
+	resp *Response
+	err  error
+}
+
+func call(ctx context.Context, cfg *config.APIConfig) chan result {
+	done := make(chan result, 1)
+	go func() {
+		resp, err := Do(ctx, cfg, Request{Method: http.MethodGet, Path: "/quote"})
+		done <- result{resp, err}
+	}()
+	return done
+}
+
+func TestCoalesceShares(t *testing.T) {
+	srv := newSlowServer(t, `{"c":1}`)
+	cfg := srv.config(t)
+	var calls []chan result
+	for range 3 {
+		calls = append(calls, call(context.Background(), cfg))
+	}
+	waitForWaiters(t, cfg, "/quote", 3)
+	close(srv.release)
+
+	shared := 0
+	for _, c := range calls {
+		r := <-c
+		if r.err != nil || r.resp.StatusCode != http.StatusOK || string(r.resp.Body) != `{"c":1}` {
+			t.Fatalf("Do = %+v, %v", r.resp, r.err)
+		}
+		if r.resp.Shared {
+			shared++
+		}
+	}
+	if n := srv.requests.Load(); n != 1 || shared != 2 {
+		t.Errorf("%d upstream requests and %d shared responses, want 1 and 2", n, shared)
+	}
+
+	// Requests with other credentials are not shared
+	other := srv.config(t)
+	other.APIKey = "other"
+	if r := <-call(context.Background(), other); r.err != nil || r.resp.Shared || srv.requests.Load() != 2 {
+		t.Errorf("request with other credentials: %+v, %v", r.resp, r.err)
+	}
+}
+
+func TestCoalesceWaiterGivesUp(t *testing.T) {
+	srv := newSlowServer(t, `{}`)
+	cfg := srv.config(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	leaving := call(ctx, cfg)
+	staying := call(context.Background(), cfg)
+	waitForWaiters(t, cfg, "/quote", 2)
+
+	cancel()
+	if r := <-leaving; !errors.Is(r.err, context.Canceled) {
+		t.Errorf("cancelled caller got %+v, %v", r.resp, r.err)
+	}
+	waitForWaiters(t, cfg, "/quote", 1)
+	close(srv.release)
+	if r := <-staying; r.err != nil || r.resp.StatusCode != http.StatusOK {
+		t.Errorf("remaining caller got %+v, %v", r.resp, r.err)
+	}
+	if n := srv.requests.Load(); n != 1 {
+		t.Errorf("%d upstream requests, want 1", n)
+	}
+}
+
+func TestCoalesceAbort(t *testing.T) {
+	srv := newSlowServer(t, `{}`)
+	cfg := srv.config(t)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	first, second := call(ctx1, cfg), call(ctx2, cfg)
+	waitForWaiters(t, cfg, "/quote", 2)
+
+	cancel1()
+	<-first
+	select {
+	case <-srv.aborted:
+		t.Fatal("request aborted while a caller still waits")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel2()
+	<-second
+	select {
+	case <-srv.aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not aborted after every caller gave up")
+	}
+
+	// A later call starts a new request instead of joining the aborted one
+	close(srv.release)
+	if r := <-call(context.Background(), cfg); r.err != nil || r.resp.Shared || srv.requests.Load() != 2 {
+		t.Errorf("call after the abort: %+v, %v", r.resp, r.err)
+	}
+}
+
+// clientSession collects the notifications sent to one MCP client.
+type clientSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *clientSession) Initialize()                                         {}
+func (s *clientSession) Initialized() bool                                   { return true }
+func (s *clientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *clientSession) SessionID() string                                   { return s.id }
+
+func TestCoalesceProgress(t *testing.T) {
+	body := `{"data":"` + strings.Repeat("x", 100_000) + `"}`
+	srv := newSlowServer(t, body)
+	cfg := srv.config(t)
+	mcpSrv := server.NewMCPServer("test", "1.0", server.WithToolHandlerMiddleware(progress.Middleware))
+	mcpSrv.AddTool(mcp.NewTool("get_quote"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := Do(ctx, cfg, Request{Method: http.MethodGet, Path: "/quote"}); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	// Each caller is a tool call with a progress token from its own session
+	var wg sync.WaitGroup
+	sessions := make([]*clientSession, 2)
+	for i := range sessions {
+		sessions[i] = &clientSession{id: string(rune('a' + i)), notifications: make(chan mcp.JSONRPCNotification, 100)}
+		ctx := mcpSrv.WithContext(context.Background(), sessions[i])
+		message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_quote","_meta":{"progressToken":%d}}}`, i)
+		wg.Go(func() {
+			if res, ok := mcpSrv.HandleMessage(ctx, []byte(message)).(mcp.JSONRPCResponse); !ok {
+				t.Errorf("call %d failed: %+v", i, res)
+			}
+		})
+		waitForWaiters(t, cfg, "/quote", i+1)
+	}
+	close(srv.release)
+	wg.Wait()
+
+	for i, s := range sessions {
+		var last mcp.JSONRPCNotification
+		for len(s.notifications) > 0 {
+			last = <-s.notifications
+		}
+		message, _ := last.Params.AdditionalFields["message"].(string)
+		if fmt.Sprint(last.Params.AdditionalFields["progressToken"]) != fmt.Sprint(i) || !strings.HasSuffix(message, "Received 97.7 kB of 97.7 kB") {
+			t.Errorf("caller %d: last progress notification %+v", i, last.Params.AdditionalFields)
+		}
+	}
+}
+
+func TestCoalesceTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	srv := newSlowServer(t, `{"c":1}`)
+	cfg := srv.config(t)
+	var traces []trace.TraceID
+	var calls []chan result
+	for i := range 2 {
+		ctx, span := provider.Tracer("test").Start(context.Background(), fmt.Sprint("call ", i))
+		defer span.End()
+		traces = append(traces, span.SpanContext().TraceID())
+		calls = append(calls, call(ctx, cfg))
+		waitForWaiters(t, cfg, "/quote", i+1)
+	}
+	close(srv.release)
+	for _, c := range calls {
+		if r := <-c; r.err != nil {
+			t.Fatal(r.err)
+		}
+	}
+
+	// Each caller gets a request span in its own trace; the one attempt
+	// belongs to the first caller's
+	requests := map[trace.TraceID]bool{}
+	attempts := map[trace.TraceID]int{}
+	for _, s := range recorder.Ended() {
+		switch s.Name() {
+		case "finnhub /quote":
+			for _, a := range s.Attributes() {
+				if a.Key == tracing.CoalescedKey {
+					requests[s.SpanContext().TraceID()] = a.Value.AsBool()
+				}
+			}
+		case "GET /quote":
+			attempts[s.SpanContext().TraceID()]++
+		}
+	}
+	if coalesced, ok := requests[traces[0]]; !ok || coalesced {
+		t.Errorf("first caller's request span: recorded %v, coalesced %v; want recorded and not coalesced", ok, coalesced)
+	}
+	if coalesced, ok := requests[traces[1]]; !ok || !coalesced {
+		t.Errorf("second caller's request span: recorded %v, coalesced %v; want recorded and coalesced", ok, coalesced)
+	}
+	if attempts[traces[0]] != 1 || attempts[traces[1]] != 0 {
+		t.Errorf("attempt spans per trace: %d and %d, want 1 and 0", attempts[traces[0]], attempts[traces[1]])
+	}
+}
@@ -0,0 +1,123 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/progress"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdle is how long the limiter of a key is kept without calls. A
+// limiter idle for longer than its per-minute window is full again, so
+// dropping it loses no quota.
+const limiterIdle = 10 * time.Minute
+
+// keyLimiter enforces the per-second and per-minute quotas of one API key.
+type keyLimiter struct {
+	perSecond *rate.Limiter
+	perMinute *rate.Limiter
+	lastUsed  time.Time // Guarded by limitersMu
+}
+
+var (
+	limitersMu    sync.Mutex
+	limiters      = map[string]*keyLimiter{}
+	limitersSwept time.Time
+)
+
+// limiterFor returns the process-wide limiter for cfg's credentials. Limiters
+// are keyed by a hash of the credentials rather than by APIConfig, because in
+// HTTP mode every /mcp request builds a fresh APIConfig for the same key.
+// If cfg carries lower limits than the existing limiter, the limiter is
+// lowered in place so queued callers keep their place; higher limits are
+// ignored until the limiter has been idle and is dropped.
+func limiterFor(cfg *config.APIConfig) *keyLimiter {
+	key := cfg.CredentialKey()
+	perSecond := limitOf(cfg.RateLimitPerSecond, time.Second)
+	perMinute := limitOf(cfg.RateLimitPerMinute, time.Minute)
+	now := time.Now()
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if now.Sub(limitersSwept) > limiterIdle {
+		for k, l := range limiters {
+			if now.Sub(l.lastUsed) > limiterIdle {
+				delete(limiters, k)
+			}
+		}
+		limitersSwept = now
+	}
+	l, ok := limiters[key]
+	if !ok {
+		l = &keyLimiter{
+			perSecond: rate.NewLimiter(perSecond, burstOf(cfg.RateLimitPerSecond)),
+			perMinute: rate.NewLimiter(perMinute, burstOf(cfg.RateLimitPerMinute)),
+		}
+		limiters[key] = l
+	}
+	l.lastUsed = now
+	if perSecond < l.perSecond.Limit() {
+		l.perSecond.SetLimit(perSecond)
+		l.perSecond.SetBurst(burstOf(cfg.RateLimitPerSecond))
+	}
+	if perMinute < l.perMinute.Limit() {
+		l.perMinute.SetLimit(perMinute)
+		l.perMinute.SetBurst(burstOf(cfg.RateLimitPerMinute))
+	}
+	return l
+}
+
+// wait blocks until both quotas allow another call and reports how long the
+// caller was queued. It fails early if ctx would expire before a slot frees up.
+func (l *keyLimiter) wait(ctx context.Context) (time.Duration, error) {
+	now := time.Now()
+	sec := l.perSecond.ReserveN(now, 1)
+	minute := l.perMinute.ReserveN(now, 1)
+	if !sec.OK() || !minute.OK() {
+		sec.CancelAt(now)
+		minute.CancelAt(now)
+		return 0, fmt.Errorf("rate limiter misconfigured")
+	}
+
+	delay := max(sec.DelayFrom(now), minute.DelayFrom(now))
+	if delay == 0 {
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		sec.CancelAt(now)
+		minute.CancelAt(now)
+		return 0, fmt.Errorf("rate limit: next slot in %s is past the call deadline", delay.Round(time.Millisecond))
+	}
+	progress.FromContext(ctx).Status(fmt.Sprintf("Queued for %s behind the rate limit", delay.Round(time.Millisecond)))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		sec.Cancel()
+		minute.Cancel()
+		return time.Since(now), ctx.Err()
+	}
+}
+
+// limitOf converts "n calls per window" into a token rate. Zero or negative
+// values disable the quota.
+func limitOf(n int, window time.Duration) rate.Limit {
+	if n <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(float64(n) / window.Seconds())
+}
+
+func burstOf(n int) int {
+	if n <= 0 {
+		return 1
+	}
+	return n
+}
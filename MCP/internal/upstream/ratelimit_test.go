@@ -0,0 +1,70 @@
+package upstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterForOnlyLowers(t *testing.T) {
+	cfg := &config.APIConfig{APIKey: "lowers", RateLimitPerSecond: 30, RateLimitPerMinute: 60}
+	l := limiterFor(cfg)
+
+	lower := *cfg
+	lower.RateLimitPerSecond, lower.RateLimitPerMinute = 5, 10
+	if limiterFor(&lower) != l {
+		t.Fatal("same key got a new limiter")
+	}
+	if got := l.perSecond.Limit(); got != 5 {
+		t.Errorf("per-second limit = %v after lowering, want 5", got)
+	}
+	if got := l.perMinute.Limit(); got != rate.Limit(10.0/60) {
+		t.Errorf("per-minute limit = %v after lowering, want 10/min", got)
+	}
+
+	// Neither the server's own limits nor disabled limits raise it again
+	limiterFor(cfg)
+	unlimited := *cfg
+	unlimited.RateLimitPerSecond, unlimited.RateLimitPerMinute = 0, 0
+	limiterFor(&unlimited)
+	if got := l.perSecond.Limit(); got != 5 {
+		t.Errorf("per-second limit = %v after a higher limit, want 5", got)
+	}
+	if got := l.perMinute.Limit(); got != rate.Limit(10.0/60) {
+		t.Errorf("per-minute limit = %v after a higher limit, want 10/min", got)
+	}
+
+	other := &config.APIConfig{APIKey: "other", RateLimitPerSecond: 30, RateLimitPerMinute: 60}
+	if got := limiterFor(other).perSecond.Limit(); got != 30 {
+		t.Errorf("another key's limit = %v, want 30", got)
+	}
+}
+
+func TestLimiterForEvictsIdle(t *testing.T) {
+	idle := &config.APIConfig{APIKey: "idle", RateLimitPerSecond: 1, RateLimitPerMinute: 1}
+	busy := &config.APIConfig{APIKey: "busy", RateLimitPerSecond: 1, RateLimitPerMinute: 1}
+	l := limiterFor(idle)
+	limiterFor(busy)
+
+	limitersMu.Lock()
+	l.lastUsed = time.Now().Add(-2 * limiterIdle)
+	limitersSwept = time.Time{}
+	limitersMu.Unlock()
+
+	limiterFor(busy)
+	limitersMu.Lock()
+	_, kept := limiters[idle.CredentialKey()]
+	_, busyKept := limiters[busy.CredentialKey()]
+	limitersMu.Unlock()
+	if kept {
+		t.Error("idle limiter was not evicted")
+	}
+	if !busyKept {
+		t.Error("limiter in use was evicted")
+	}
+	if limiterFor(idle) == l {
+		t.Error("evicted limiter was reused")
+	}
+}
@@ -0,0 +1,128 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+)
+
+// retryable reports whether a failed attempt is worth repeating: network
+// errors, per-attempt timeouts, rate limiting and transient gateway errors.
+// ctx is the call context; once it is done nothing is retried.
+func retryable(ctx context.Context, resp *Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return transient(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transient reports whether err is a network failure that may not happen
+// again: a refused, reset or dropped connection, a DNS or dial failure, or
+// an attempt that ran out of time. Local errors, such as an invalid URL or
+// an oversized response, fail the same way on every attempt.
+func transient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	// url.Error is a net.Error itself whatever it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff returns how long to wait before the next attempt. Server hints in
+// Retry-After or Finnhub's X-Ratelimit-Reset win; otherwise the delay grows
+// exponentially from cfg.RetryBaseDelay with jitter, capped at cfg.RetryMaxDelay.
+func backoff(cfg *config.APIConfig, attempt int, resp *Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header, time.Now()); ok {
+			return d
+		}
+	}
+
+	base := cfg.RetryBaseDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	d := base << (attempt - 1)
+	if cfg.RetryMaxDelay > 0 && (d > cfg.RetryMaxDelay || d <= 0) {
+		d = cfg.RetryMaxDelay
+	}
+	// Equal jitter: somewhere between half and the full delay
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses Retry-After (seconds or HTTP date) and X-Ratelimit-Reset
+// (UNIX seconds) relative to now.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return max(time.Duration(secs)*time.Second, 0), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+	if v := h.Get("X-Ratelimit-Reset"); v != "" {
+		if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return max(time.Unix(unix, 0).Sub(now), 0), true
+		}
+	}
+	return 0, false
+}
+
+// sleep waits for d unless ctx finishes first or would expire before d
+// elapses, in which case it returns false without waiting.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// failure describes why an attempt failed, for progress messages.
+func failure(resp *Response, err error) string {
+	if err == nil {
+		return "HTTP " + strconv.Itoa(resp.StatusCode)
+	}
+	// Not the URL, which url.Error would repeat
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+func attemptsString(n int) string {
+	if n == 1 {
+		return "1 attempt"
+	}
+	return strconv.Itoa(n) + " attempts"
+}
@@ -0,0 +1,219 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+		ok     bool
+	}{
+		{"none", nil, 0, false},
+		{"seconds", map[string]string{"Retry-After": "7"}, 7 * time.Second, true},
+		{"zero seconds", map[string]string{"Retry-After": "0"}, 0, true},
+		{"negative seconds", map[string]string{"Retry-After": "-3"}, 0, true},
+		{"HTTP date", map[string]string{"Retry-After": now.Add(90 * time.Second).Format(http.TimeFormat)}, 90 * time.Second, true},
+		{"past HTTP date", map[string]string{"Retry-After": now.Add(-time.Hour).Format(http.TimeFormat)}, 0, true},
+		{"RFC 850 date", map[string]string{"Retry-After": now.Add(time.Minute).Format(time.RFC850)}, time.Minute, true},
+		{"invalid", map[string]string{"Retry-After": "soon"}, 0, false},
+		{"ratelimit reset", map[string]string{"X-Ratelimit-Reset": strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)}, 30 * time.Second, true},
+		{"past ratelimit reset", map[string]string{"X-Ratelimit-Reset": strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)}, 0, true},
+		{"invalid ratelimit reset", map[string]string{"X-Ratelimit-Reset": "later"}, 0, false},
+		{"Retry-After wins", map[string]string{
+			"Retry-After":       "5",
+			"X-Ratelimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10),
+		}, 5 * time.Second, true},
+		{"invalid Retry-After falls back", map[string]string{
+			"Retry-After":       "soon",
+			"X-Ratelimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10),
+		}, time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.header {
+				h.Set(k, v)
+			}
+			got, ok := retryAfter(h, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("retryAfter = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    time.Duration
+		max     time.Duration
+		attempt int
+		want    time.Duration // The delay before jitter, which takes off up to half
+	}{
+		{"first", 100 * time.Millisecond, 10 * time.Second, 1, 100 * time.Millisecond},
+		{"second", 100 * time.Millisecond, 10 * time.Second, 2, 200 * time.Millisecond},
+		{"fourth", 100 * time.Millisecond, 10 * time.Second, 4, 800 * time.Millisecond},
+		{"capped", 100 * time.Millisecond, time.Second, 5, time.Second},
+		{"overflow capped", 100 * time.Millisecond, time.Second, 80, time.Second},
+		{"default base", 0, 10 * time.Second, 1, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.APIConfig{RetryBaseDelay: tt.base, RetryMaxDelay: tt.max}
+			for range 100 {
+				got := backoff(cfg, tt.attempt, nil)
+				if got < tt.want/2 || got > tt.want {
+					t.Fatalf("backoff = %v, want between %v and %v", got, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+
+	// A server hint wins over the computed delay
+	cfg := &config.APIConfig{RetryBaseDelay: time.Millisecond, RetryMaxDelay: time.Second}
+	resp := &Response{Header: http.Header{"Retry-After": {"3"}}}
+	if got := backoff(cfg, 1, resp); got != 3*time.Second {
+		t.Errorf("backoff with Retry-After = %v, want 3s", got)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Nothing listens on a closed server's address
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	_, refused := http.Get(srv.URL)
+	_, badScheme := http.Get("finnhub://quote")
+	_, badURL := http.NewRequest(http.MethodGet, "http://[::1", nil)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *Response
+		err  error
+		want bool
+	}{
+		{"connection refused", context.Background(), nil, refused, true},
+		{"connection reset", context.Background(), nil, &url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, true},
+		{"dropped connection", context.Background(), nil, &url.Error{Op: "Get", URL: "http://x", Err: io.EOF}, true},
+		{"unsupported scheme", context.Background(), nil, badScheme, false},
+		{"invalid URL", context.Background(), nil, fmt.Errorf("failed to create request: %w", badURL), false},
+		{"local error", context.Background(), nil, errors.New("response larger than 64 MB"), false},
+		{"attempt timeout", context.Background(), nil, context.DeadlineExceeded, true},
+		{"cancelled attempt", context.Background(), nil, context.Canceled, false},
+		{"call done", cancelled, &Response{StatusCode: http.StatusServiceUnavailable}, nil, false},
+		{"429", context.Background(), &Response{StatusCode: http.StatusTooManyRequests}, nil, true},
+		{"502", context.Background(), &Response{StatusCode: http.StatusBadGateway}, nil, true},
+		{"503", context.Background(), &Response{StatusCode: http.StatusServiceUnavailable}, nil, true},
+		{"504", context.Background(), &Response{StatusCode: http.StatusGatewayTimeout}, nil, true},
+		{"500", context.Background(), &Response{StatusCode: http.StatusInternalServerError}, nil, false},
+		{"403", context.Background(), &Response{StatusCode: http.StatusForbidden}, nil, false},
+		{"200", context.Background(), &Response{StatusCode: http.StatusOK}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.ctx, tt.resp, tt.err); got != tt.want {
+				t.Errorf("retryable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool
+		failures     int // 503 responses before a 200
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"GET recovers", http.MethodGet, false, 2, 3, http.StatusOK},
+		{"GET gives up", http.MethodGet, false, 5, 3, http.StatusServiceUnavailable},
+		{"POST not retried", http.MethodPost, false, 2, 1, http.StatusServiceUnavailable},
+		{"idempotent POST retried", http.MethodPost, true, 2, 3, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(requests.Add(1)) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			cfg := &config.APIConfig{
+				BaseURL:          srv.URL,
+				APIKey:           t.Name(),
+				RetryMaxAttempts: 3,
+				RetryBaseDelay:   time.Millisecond,
+				RetryMaxDelay:    2 * time.Millisecond,
+			}
+			resp, err := Do(context.Background(), cfg, Request{Method: tt.method, Path: "/x", Idempotent: tt.idempotent})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.Attempts != tt.wantAttempts || int(requests.Load()) != tt.wantAttempts {
+				t.Errorf("attempts = %d, requests = %d, want %d", resp.Attempts, requests.Load(), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDoReportsAttempts(t *testing.T) {
+	// Nothing listens on a closed server's address
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{http.MethodGet, "after 3 attempts"},
+		{http.MethodPost, "after 1 attempt:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			cfg := &config.APIConfig{
+				BaseURL:          srv.URL,
+				APIKey:           "secret-" + tt.method,
+				APIKeyIn:         config.APIKeyInQuery,
+				RetryMaxAttempts: 3,
+				RetryBaseDelay:   time.Millisecond,
+				RetryMaxDelay:    2 * time.Millisecond,
+			}
+			_, err := Do(context.Background(), cfg, Request{Method: tt.method, Path: "/x"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.want)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("error leaks the API key: %v", err)
+			}
+		})
+	}
+}
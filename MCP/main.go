@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"log"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -10,15 +16,64 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/finnhub-api/mcp-server/completions"
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/access"
+	"github.com/finnhub-api/mcp-server/internal/cache"
+	"github.com/finnhub-api/mcp-server/internal/live"
+	"github.com/finnhub-api/mcp-server/internal/logging"
+	"github.com/finnhub-api/mcp-server/internal/metrics"
+	"github.com/finnhub-api/mcp-server/internal/progress"
+	"github.com/finnhub-api/mcp-server/internal/session"
+	"github.com/finnhub-api/mcp-server/internal/tracing"
+	"github.com/finnhub-api/mcp-server/prompts"
+	"github.com/finnhub-api/mcp-server/resources"
 )
 
 func main() {
+	toolsets := flag.String("toolsets", "", "comma-separated toolsets to enable (overrides TOOLSETS)")
+	tools := flag.String("tools", "", "comma-separated tools to enable in addition to the toolsets (overrides TOOLS)")
+	excludeTools := flag.String("exclude-tools", "", "comma-separated tools to disable (overrides EXCLUDE_TOOLS)")
+	flag.Parse()
+
 	cfg, err := config.LoadAPIConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("Failed to load config", "error", err)
+	}
+	logging.Setup(cfg)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "toolsets":
+			cfg.Tools.Toolsets = config.SplitList(*toolsets)
+		case "tools":
+			cfg.Tools.Tools = config.SplitList(*tools)
+		case "exclude-tools":
+			cfg.Tools.Exclude = config.SplitList(*excludeTools)
+		}
+	})
+	if err := checkToolSelection(&cfg.Tools, toolIndex(GetAll(cfg))); err != nil {
+		fatal("Invalid tool selection", "error", err)
+	}
+	if err := checkCacheTTLs(cfg.CacheTTLs, GetAll(cfg)); err != nil {
+		fatal("Invalid CACHE_TTLS", "error", err)
+	}
+	if _, err := cache.For(cfg); err != nil {
+		fatal("Failed to open response cache", "error", err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
 	}
+	defer func() {
+		// Export the spans still buffered
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Tracing shutdown error", "error", err)
+		}
+	}()
 
 	// Check transport environment variable (both uppercase and lowercase)
 	transport := os.Getenv("TRANSPORT")
@@ -32,7 +87,7 @@ func main() {
 	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" {
 		port := cfg.Port
 		if port == "" {
-			log.Fatalf("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
+			fatal("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
 		}
 
 		// Determine if HTTPS mode and normalize transport
@@ -43,35 +98,88 @@ func main() {
 			transport = "HTTP"
 		}
 		
-		log.Printf("Running in %s mode on port %s", transport, port)
+		slog.Info("Running in "+transport+" mode", "port", port)
+
+		accessCfg, err := config.LoadAccessConfig()
+		if err != nil {
+			fatal("Failed to load access config", "error", err)
+		}
+		tlsCfg, err := access.TLSConfig(accessCfg)
+		if err != nil {
+			fatal("Failed to load access config", "error", err)
+		}
+		if tlsCfg != nil && !isHTTPS {
+			fatal("CLIENT_CA_FILE requires HTTPS mode")
+		}
+		if !accessCfg.Enabled() && tlsCfg == nil {
+			slog.Warn("/mcp accepts unauthenticated requests; set MCP_AUTH_TOKENS or OAUTH_JWKS_URL to require authentication")
+		}
+		guard := access.New(accessCfg)
+
+		// One MCP server serves every session. The session store keeps each
+		// session's API configuration, which the /mcp handler attaches to
+		// the session's requests.
+		sessions := session.NewStore(cfg.SessionIdleTimeout)
+		defer sessions.Close()
+		mcpSrv := createMCPServer(cfg, transport)
+		// Names that sessions may select, checked on every initialize
+		knownTools := toolIndex(GetAll(cfg))
+		// Evicted sessions end their subscriptions like deleted ones
+		sessions.OnEvict(func(id string) {
+			mcpSrv.UnregisterSession(context.Background(), id)
+		})
+		metrics.Sessions(sessions.Len)
+		handler := server.NewStreamableHTTPServer(mcpSrv,
+			server.WithSessionIdManager(sessions),
+			server.WithHTTPContextFunc(sessions.HTTPContext),
+		)
 
 		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
+		mux.Handle("/mcp", guard.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			initialize, err := isInitialize(w, r)
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
 			}
-
-			if apiCfg.BaseURL == "" {
-				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+			if id := r.Header.Get(server.HeaderKeySessionID); id != "" && !initialize {
+				apiCfg, release, ok := sessions.Acquire(id, access.Principal(r.Context()))
+				if !ok {
+					// Unknown, evicted or someone else's: the client has to initialize again
+					http.Error(w, "Session not found", http.StatusNotFound)
+					return
+				}
+				defer release()
+				handler.ServeHTTP(w, r.WithContext(config.NewContext(r.Context(), apiCfg)))
 				return
 			}
 
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
+			// A new session, even when the client sent an old session's ID;
+			// its API configuration comes from these headers
+			apiCfg, err := cfg.ForRequest(r.Header)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
+			if apiCfg.SessionTools != nil {
+				if err := checkToolSelection(apiCfg.SessionTools, knownTools); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
 
-			handler.ServeHTTP(w, r)
-		})
+			// The session store binds this configuration to the session it
+			// creates for the request
+			handler.ServeHTTP(w, r.WithContext(config.NewContext(r.Context(), apiCfg)))
+		})))
+		mux.Handle("/metrics", metrics.Handler())
+		mux.HandleFunc(access.MetadataPath, guard.MetadataHandler)
+		mux.HandleFunc(access.MetadataPath+"/mcp", guard.MetadataHandler)
 
 		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -79,7 +187,7 @@ func main() {
 		})
 
 		addr := net.JoinHostPort("0.0.0.0", port)
-		httpServer := &http.Server{Addr: addr, Handler: mux}
+		httpServer := &http.Server{Addr: addr, Handler: mux, TLSConfig: tlsCfg}
 
 		go func() {
 			// Check if HTTPS mode
@@ -88,58 +196,162 @@ func main() {
 				keyFile := os.Getenv("KEY_FILE")
 				
 				if certFile == "" || keyFile == "" {
-					log.Fatalf("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
+					fatal("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
 				}
 				
-				log.Printf("Starting HTTPS server on %s", addr)
+				slog.Info("Starting HTTPS server", "addr", addr)
 				if err := httpServer.ListenAndServeTLS(certFile, keyFile); err != http.ErrServerClosed {
-					log.Fatalf("HTTPS server error: %v", err)
+					fatal("HTTPS server error", "error", err)
 				}
 			} else {
-				log.Printf("Starting HTTP server on %s", addr)
+				slog.Info("Starting HTTP server", "addr", addr)
 				if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
-					log.Fatalf("HTTP server error: %v", err)
+					fatal("HTTP server error", "error", err)
 				}
 			}
 		}()
 
 		<-sigChan
-		log.Println("Shutdown signal received")
+		slog.Info("Shutdown signal received")
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := httpServer.Shutdown(ctx); err != nil {
-			log.Printf("Shutdown error: %v", err)
+			slog.Error("Shutdown error", "error", err)
 		} else {
-			log.Println("HTTP server shutdown complete")
+			slog.Info("HTTP server shutdown complete")
 		}
 		return
 	}
 
 	// STDIO Mode - default when no transport or transport is "stdio"
-	log.Println("Running in STDIO mode")
+	slog.Info("Running in STDIO mode")
 	mcp := createMCPServer(cfg, "STDIO")
 	go func() {
 		if err := server.ServeStdio(mcp); err != nil {
-			log.Fatalf("STDIO error: %v", err)
+			fatal("STDIO error", "error", err)
 		}
 	}()
 	<-sigChan
-	log.Println("Received shutdown signal. Exiting STDIO mode.")
+	slog.Info("Received shutdown signal. Exiting STDIO mode.")
+}
+
+// fatal logs msg and its attributes as an error and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// maxBodyBytes limits the body of an MCP request.
+const maxBodyBytes = 1 << 20
+
+// isInitialize reports whether r is a POST carrying an initialize request.
+// The body, limited to maxBodyBytes, is read and replaced so the MCP handler
+// can still read it.
+func isInitialize(w http.ResponseWriter, r *http.Request) (bool, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return false, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		return false, fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	var msg struct {
+		Method mcp.MCPMethod `json:"method"`
+	}
+	// Batches and malformed bodies are left to the MCP handler to reject
+	_ = json.Unmarshal(body, &msg)
+	return msg.Method == mcp.MethodInitialize, nil
 }
 
 func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
+	tools := GetAll(cfg)
+	index := toolIndex(tools)
+	gate := sessionToolGate(cfg, index)
+	usable := sessionToolUsable(cfg, index)
+
+	// Quote resources can be subscribed to when get_quote is available
+	quote, ok := index["get_quote"]
+	subscribe := ok && cfg.ToolEnabled(quote.Definition.Name, quote.Toolset) && cfg.MaxSubscriptions > 0
+	hooks := &server.Hooks{}
+
+	// Prompts are offered when every tool they call for can be called
+	offered := prompts.Prompts()
+	needs := make(map[string][]string, len(offered))
+	for _, p := range offered {
+		needs[p.Definition.Name] = p.Tools
+	}
+
+	// Prompt and resource arguments are completed from reference lists
+	handlers := make(map[string]server.ToolHandlerFunc, len(index))
+	for name, tool := range index {
+		handlers[name] = tool.Handler
+	}
+	completer := completions.New(handlers, usable)
+
 	mcp := server.NewMCPServer("Finnhub API", "1.0.0",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(subscribe, false),
+		server.WithPromptCapabilities(false),
+		server.WithCompletions(),
+		server.WithLogging(),
+		server.WithPromptCompletionProvider(completer),
+		server.WithResourceCompletionProvider(completer),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
+		// Sessions may narrow the server's tool selection at initialize, and
+		// tools outside the key's plan are hidden or annotated
+		server.WithToolFilter(sessionToolFilter(cfg, index)),
+		server.WithToolHandlerMiddleware(tracing.Middleware),
+		server.WithToolHandlerMiddleware(metrics.Middleware),
+		server.WithToolHandlerMiddleware(logging.Middleware),
+		server.WithToolHandlerMiddleware(gate),
+		server.WithToolHandlerMiddleware(progress.Middleware),
+		server.WithToolHandlerMiddleware(cache.Middleware(cfg)),
+		server.WithPromptFilter(sessionPromptFilter(usable, needs)),
 	)
 
-	tools := GetAll(cfg)
-	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
-
+	loaded := 0
 	for _, tool := range tools {
-		mcp.AddTool(tool.Definition, tool.Handler)
+		if cfg.ToolEnabled(tool.Definition.Name, tool.Toolset) {
+			definition := tool.Definition
+			if cfg.CacheTTL(definition.Name) > 0 {
+				// Cached tools take a cache argument to fetch a fresh response
+				definition = cache.WithBypassArgument(definition)
+			}
+			mcp.AddTool(definition, tool.Handler)
+			loaded++
+		}
+	}
+	slog.Info("Loaded tools", "mode", mode, "loaded", loaded, "available", len(tools))
+	if cfg.CacheBackend != config.CacheOff {
+		slog.Info("Response cache enabled", "backend", cfg.CacheBackend)
+	}
+
+	// Resources read through the same checks as tool calls
+	for _, t := range resources.Templates() {
+		tool, ok := index[t.Tool]
+		if ok && cfg.ToolEnabled(tool.Definition.Name, tool.Toolset) {
+			mcp.AddResourceTemplate(t.Definition, t.Handler(gate(tool.Handler)))
+		}
+	}
+
+	for _, p := range offered {
+		mcp.AddPrompt(p.Definition, p.Handler())
+	}
+
+	if subscribe {
+		// Market hours are read through the same checks, so a session that
+		// cannot call get_stock_market-status polls around the clock
+		var marketStatus server.ToolHandlerFunc
+		if status, ok := index["get_stock_market-status"]; ok && cfg.ToolEnabled(status.Definition.Name, status.Toolset) {
+			marketStatus = gate(status.Handler)
+		}
+		hub := live.New(cfg, gate(quote.Handler), marketStatus)
+		hub.Register(mcp, hooks)
+		slog.Info("Quote subscriptions enabled", "feed", cfg.QuoteFeed, "max_per_session", cfg.MaxSubscriptions)
 	}
 
 	return mcp
-}
\ No newline at end of file
+}
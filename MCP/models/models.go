@@ -2,10 +2,20 @@ package models
 
 import (
 	"context"
+
+	"github.com/finnhub-api/mcp-server/internal/plan"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type Tool struct {
 	Definition mcp.Tool
 	Handler    func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	Toolset    string      // Group the tool belongs to, e.g. "stock-price"
+	Access     plan.Access // Plan tier the endpoint requires
+}
+
+// Toolset is a group of related tools that can be enabled together.
+type Toolset struct {
+	Name  string // Identifier used in TOOLSETS, e.g. "stock-price"
+	Title string // Section of the Finnhub documentation, e.g. "Stock Price"
 }
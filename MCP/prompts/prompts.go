@@ -0,0 +1,186 @@
+// Package prompts offers MCP prompts for common analyst workflows. Each
+// prompt is a parameterized instruction that names the tools to call, in
+// order and with their arguments, and what to make of the results, so
+// clients do not have to write these instructions themselves.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dateLayout is the date format Finnhub's from and to arguments expect.
+const dateLayout = "2006-01-02"
+
+// Prompt is a prompt and the tools its instructions call for.
+type Prompt struct {
+	Definition mcp.Prompt
+	Tools      []string // Every tool the instructions call, e.g. get_quote
+
+	// check, if set, validates the prompt arguments before they are
+	// rendered.
+	check func(args map[string]string) error
+
+	// text renders the instructions from the prompt arguments, which have
+	// been checked to include every required argument.
+	text func(args map[string]string, today time.Time) string
+}
+
+// Prompts lists the prompts the server offers.
+func Prompts() []Prompt {
+	return []Prompt{
+		{
+			Definition: mcp.NewPrompt("earnings_preview",
+				mcp.WithPromptTitle("Earnings preview"),
+				mcp.WithPromptDescription("Preview a company's upcoming earnings report: the report date, consensus estimates, recent beats and misses, and analyst price targets."),
+				symbolArgument("Stock symbol of the company, e.g. AAPL"),
+			),
+			Tools: []string{"get_calendar_earnings", "get_stock_eps-estimate", "get_stock_earnings", "get_stock_price-target"},
+			text: func(args map[string]string, today time.Time) string {
+				symbol := args["symbol"]
+				return fmt.Sprintf(`Prepare an earnings preview for %[1]s.
+
+1. Call get_calendar_earnings with symbol=%[1]s, from=%[2]s and to=%[3]s to find the date and time of the next report and the EPS and revenue consensus for it. If nothing is scheduled, say so and preview the most recent report instead.
+2. Call get_stock_eps-estimate with symbol=%[1]s and freq=quarterly for the EPS consensus of the coming quarters: the average, the range between low and high, and the number of analysts.
+3. Call get_stock_earnings with symbol=%[1]s and limit=8 for the last quarters' actual EPS against the estimate.
+4. Call get_stock_price-target with symbol=%[1]s for the analysts' high, low, mean and median price targets.
+
+Then write the preview: when the company reports, what the street expects, how the company fared against expectations in recent quarters (beats, misses and the size of the surprises), and where analysts see the stock going. Point out wide estimate ranges and a pattern of surprises in either direction. Quote figures with their period and cite the tool each comes from; do not fill in numbers the tools did not return.`,
+					symbol, today.Format(dateLayout), today.AddDate(0, 0, 90).Format(dateLayout))
+			},
+		},
+		{
+			Definition: mcp.NewPrompt("insider_activity_review",
+				mcp.WithPromptTitle("Insider activity review"),
+				mcp.WithPromptDescription("Review recent insider buying and selling of a company and the sentiment it signals."),
+				symbolArgument("Stock symbol of the company, e.g. AAPL"),
+				mcp.WithArgument("days",
+					mcp.ArgumentDescription("Number of days to look back (default 90)"),
+				),
+			),
+			Tools: []string{"get_stock_insider-transactions", "get_stock_insider-sentiment", "get_quote"},
+			text: func(args map[string]string, today time.Time) string {
+				symbol := args["symbol"]
+				days := count(args["days"], 90)
+				return fmt.Sprintf(`Review insider activity at %[1]s over the last %[4]d days.
+
+1. Call get_stock_insider-transactions with symbol=%[1]s, from=%[2]s and to=%[3]s for the individual filings: who traded, the transaction code, the number of shares and the price.
+2. Call get_stock_insider-sentiment with symbol=%[1]s, from=%[5]s and to=%[3]s for the monthly share purchase ratio (MSPR), which ranges from -100 (heavy selling) to 100 (heavy buying), and the net share change.
+3. Call get_quote with symbol=%[1]s for the current price, to compare with the prices insiders paid or received.
+
+Then summarize: net buying or selling and by whom, the largest transactions, and how this period's MSPR compares with the previous months. Separate open market purchases (code P) and sales (code S) from option exercises, grants and tax withholding, which say little about an insider's view. Mention clusters of trades by several insiders around the same date. Do not read intent into routine or scheduled sales.`,
+					symbol, today.AddDate(0, 0, -days).Format(dateLayout), today.Format(dateLayout), days,
+					today.AddDate(-1, 0, 0).Format(dateLayout))
+			},
+		},
+		{
+			Definition: mcp.NewPrompt("etf_look_through",
+				mcp.WithPromptTitle("ETF look-through"),
+				mcp.WithPromptDescription("Look through an ETF to what it actually holds: its largest positions, concentration, and sector and country exposure."),
+				symbolArgument("ETF symbol, e.g. SPY"),
+				mcp.WithArgument("top",
+					mcp.ArgumentDescription("Number of largest holdings to discuss (default 10)"),
+				),
+			),
+			Tools: []string{"get_etf_profile", "get_etf_holdings", "get_etf_sector", "get_etf_country"},
+			text: func(args map[string]string, today time.Time) string {
+				symbol := args["symbol"]
+				top := count(args["top"], 10)
+				return fmt.Sprintf(`Look through the ETF %[1]s to what it holds.
+
+1. Call get_etf_profile with symbol=%[1]s for its benchmark, assets under management, expense ratio and number of holdings.
+2. Call get_etf_holdings with symbol=%[1]s for its current holdings and their weights.
+3. Call get_etf_sector with symbol=%[1]s for its sector exposure.
+4. Call get_etf_country with symbol=%[1]s for its country exposure.
+
+Then describe the fund: list its %[2]d largest holdings with their weights, and the combined weight of those %[2]d against the rest. Call out concentration in single names, sectors or countries, and anything that does not match what the fund's name or benchmark suggests. Note the date of the holdings, since they may lag the fund's current portfolio.`,
+					symbol, top)
+			},
+		},
+		{
+			Definition: mcp.NewPrompt("compare_with_peers",
+				mcp.WithPromptTitle("Compare with peers"),
+				mcp.WithPromptDescription("Compare a company with its peers on valuation, growth, profitability and price performance."),
+				symbolArgument("Stock symbol of the company, e.g. AAPL"),
+				mcp.WithArgument("grouping",
+					mcp.ArgumentDescription("How peers are chosen: sector, industry or subIndustry (default subIndustry)"),
+				),
+			),
+			Tools: []string{"get_stock_peers", "get_stock_profile2", "get_stock_metric", "get_quote"},
+			check: func(args map[string]string) error {
+				switch args["grouping"] {
+				case "", "sector", "industry", "subIndustry":
+					return nil
+				}
+				return fmt.Errorf("invalid grouping %q: must be sector, industry or subIndustry", args["grouping"])
+			},
+			text: func(args map[string]string, today time.Time) string {
+				symbol := args["symbol"]
+				grouping := args["grouping"]
+				if grouping == "" {
+					grouping = "subIndustry"
+				}
+				return fmt.Sprintf(`Compare %[1]s with its peers.
+
+1. Call get_stock_peers with symbol=%[1]s and grouping=%[2]s for the list of peers. Keep %[1]s and up to five peers, preferring the largest.
+2. For each company, call get_stock_profile2 with its symbol for its name, industry and market capitalization.
+3. For each company, call get_stock_metric with its symbol and metric=all for its valuation (P/E, P/S, EV/EBITDA), growth (revenue and EPS growth), profitability (gross, operating and net margin, ROE) and 52-week price performance.
+4. For each company, call get_quote with its symbol for the current price and today's change.
+
+Then present a table with one row per company and one column per measure, followed by a short comparison: where %[1]s trades at a premium or a discount to its peers, and whether its growth and margins justify it. Leave a cell empty rather than estimating when a metric is missing, and note which peers are not really comparable.`,
+					symbol, grouping)
+			},
+		},
+	}
+}
+
+// symbolArgument is the required symbol argument of a prompt.
+func symbolArgument(description string) mcp.PromptOption {
+	return mcp.WithArgument("symbol",
+		mcp.ArgumentTitle("Symbol"),
+		mcp.ArgumentDescription(description),
+		mcp.RequiredArgument(),
+	)
+}
+
+// count parses a positive number argument, or returns def when the
+// argument is missing or invalid.
+func count(arg string, def int) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// Handler renders the prompt.
+func (p Prompt) Handler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := make(map[string]string, len(request.Params.Arguments))
+		for name, value := range request.Params.Arguments {
+			args[name] = strings.TrimSpace(value)
+		}
+		for _, arg := range p.Definition.Arguments {
+			if arg.Required && args[arg.Name] == "" {
+				return nil, fmt.Errorf("missing required argument %q", arg.Name)
+			}
+		}
+		if p.check != nil {
+			if err := p.check(args); err != nil {
+				return nil, err
+			}
+		}
+		if symbol, ok := args["symbol"]; ok {
+			args["symbol"] = strings.ToUpper(symbol)
+		}
+		return mcp.NewGetPromptResult(p.Definition.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(p.text(args, time.Now()))),
+		}), nil
+	}
+}
@@ -1,3 +1,5 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package main
 
 import (
@@ -8,113 +10,131 @@ import (
 
 func GetAll(cfg *config.APIConfig) []models.Tool {
 	return []models.Tool{
-		tools_default.CreateHistorical_employee_countTool(cfg),
-		tools_default.CreateEtfs_country_exposureTool(cfg),
-		tools_default.CreateEconomic_calendarTool(cfg),
-		tools_default.CreateBond_tickTool(cfg),
-		tools_default.CreateInsider_transactionsTool(cfg),
-		tools_default.CreateIsin_changeTool(cfg),
-		tools_default.CreateCompany_peersTool(cfg),
-		tools_default.CreateForex_ratesTool(cfg),
-		tools_default.CreateForex_symbolsTool(cfg),
-		tools_default.CreateMutual_fund_holdingsTool(cfg),
-		tools_default.CreateAi_chatTool(cfg),
-		tools_default.CreateNews_sentimentTool(cfg),
-		tools_default.CreateIpo_calendarTool(cfg),
-		tools_default.CreateStock_dividendsTool(cfg),
+		tools_default.CreateSymbol_searchTool(cfg),
 		tools_default.CreateStock_symbolsTool(cfg),
-		tools_default.CreateSymbol_changeTool(cfg),
-		tools_default.CreateStock_nbboTool(cfg),
-		tools_default.CreateCrypto_candlesTool(cfg),
-		tools_default.CreateGlobal_filings_search_filterTool(cfg),
-		tools_default.CreateSimilarity_indexTool(cfg),
-		tools_default.CreateTranscriptsTool(cfg),
-		tools_default.CreateStock_splitsTool(cfg),
-		tools_default.CreateStock_bidaskTool(cfg),
-		tools_default.CreateRevenue_breakdown2Tool(cfg),
-		tools_default.CreateAirline_price_indexTool(cfg),
+		tools_default.CreateMarket_statusTool(cfg),
+		tools_default.CreateMarket_holidayTool(cfg),
+		tools_default.CreateCompany_profileTool(cfg),
+		tools_default.CreateCompany_profile2Tool(cfg),
 		tools_default.CreateCompany_executiveTool(cfg),
-		tools_default.CreateSupport_resistanceTool(cfg),
-		tools_default.CreateInsider_sentimentTool(cfg),
-		tools_default.CreateAggregate_indicatorTool(cfg),
-		tools_default.CreateBond_priceTool(cfg),
-		tools_default.CreateInvestment_themesTool(cfg),
-		tools_default.CreateBond_profileTool(cfg),
-		tools_default.CreateSymbol_searchTool(cfg),
-		tools_default.CreateFda_committee_meeting_calendarTool(cfg),
-		tools_default.CreateFilingsTool(cfg),
+		tools_default.CreateMarket_newsTool(cfg),
+		tools_default.CreateCompany_newsTool(cfg),
+		tools_default.CreatePress_releasesTool(cfg),
+		tools_default.CreateNews_sentimentTool(cfg),
+		tools_default.CreateCompany_peersTool(cfg),
+		tools_default.CreateCompany_basic_financialsTool(cfg),
+		tools_default.CreateOwnershipTool(cfg),
+		tools_default.CreateFund_ownershipTool(cfg),
+		tools_default.CreateInstitutional_profileTool(cfg),
 		tools_default.CreateInstitutional_portfolioTool(cfg),
-		tools_default.CreateCompany_ebitda_estimatesTool(cfg),
-		tools_default.CreateCompany_eps_estimatesTool(cfg),
-		tools_default.CreatePrice_targetTool(cfg),
-		tools_default.CreateMutual_fund_country_exposureTool(cfg),
-		tools_default.CreatePattern_recognitionTool(cfg),
-		tools_default.CreateStock_basic_dividendsTool(cfg),
 		tools_default.CreateInstitutional_ownershipTool(cfg),
-		tools_default.CreateQuoteTool(cfg),
-		tools_default.CreateStock_visa_applicationTool(cfg),
-		tools_default.CreateStock_tickTool(cfg),
+		tools_default.CreateInsider_transactionsTool(cfg),
+		tools_default.CreateInsider_sentimentTool(cfg),
+		tools_default.CreateFinancialsTool(cfg),
+		tools_default.CreateFinancials_reportedTool(cfg),
+		tools_default.CreateRevenue_breakdownTool(cfg),
+		tools_default.CreateFilingsTool(cfg),
+		tools_default.CreateFilings_sentimentTool(cfg),
+		tools_default.CreateSimilarity_indexTool(cfg),
+		tools_default.CreateIpo_calendarTool(cfg),
+		tools_default.CreateStock_dividendsTool(cfg),
+		tools_default.CreateSector_metricTool(cfg),
 		tools_default.CreatePrice_metricsTool(cfg),
-		tools_default.CreateEconomic_codeTool(cfg),
-		tools_default.CreateCongressional_tradingTool(cfg),
+		tools_default.CreateSymbol_changeTool(cfg),
+		tools_default.CreateIsin_changeTool(cfg),
+		tools_default.CreateHistorical_market_capTool(cfg),
+		tools_default.CreateHistorical_employee_countTool(cfg),
 		tools_default.CreateRecommendation_trendsTool(cfg),
+		tools_default.CreatePrice_targetTool(cfg),
 		tools_default.CreateUpgrade_downgradeTool(cfg),
-		tools_default.CreateEarnings_calendarTool(cfg),
-		tools_default.CreateCrypto_symbolsTool(cfg),
-		tools_default.CreateCrypto_exchangesTool(cfg),
-		tools_default.CreateCompany_profile2Tool(cfg),
-		tools_default.CreateFilings_sentimentTool(cfg),
-		tools_default.CreateMarket_holidayTool(cfg),
-		tools_default.CreateCompany_esg_scoreTool(cfg),
-		tools_default.CreateEtfs_profileTool(cfg),
-		tools_default.CreateForex_exchangesTool(cfg),
-		tools_default.CreateSector_metricTool(cfg),
-		tools_default.CreateFinancialsTool(cfg),
+		tools_default.CreateCompany_revenue_estimatesTool(cfg),
+		tools_default.CreateCompany_eps_estimatesTool(cfg),
+		tools_default.CreateCompany_ebitda_estimatesTool(cfg),
+		tools_default.CreateCompany_ebit_estimatesTool(cfg),
 		tools_default.CreateCompany_earningsTool(cfg),
-		tools_default.CreatePress_releasesTool(cfg),
-		tools_default.CreateCountryTool(cfg),
-		tools_default.CreateCovid_19Tool(cfg),
-		tools_default.CreateEarnings_call_liveTool(cfg),
-		tools_default.CreateInternational_filingsTool(cfg),
-		tools_default.CreateGlobal_filings_downloadTool(cfg),
+		tools_default.CreateEarnings_calendarTool(cfg),
+		tools_default.CreateQuoteTool(cfg),
 		tools_default.CreateStock_candlesTool(cfg),
-		tools_default.CreateEconomic_dataTool(cfg),
-		tools_default.CreateMarket_statusTool(cfg),
-		tools_default.CreateGlobal_filings_searchTool(cfg),
-		tools_default.CreateTechnical_indicatorTool(cfg),
-		tools_default.CreateForex_candlesTool(cfg),
+		tools_default.CreateStock_tickTool(cfg),
+		tools_default.CreateStock_nbboTool(cfg),
+		tools_default.CreateStock_bidaskTool(cfg),
+		tools_default.CreateStock_splitsTool(cfg),
+		tools_default.CreateStock_basic_dividendsTool(cfg),
+		tools_default.CreateIndices_constituentsTool(cfg),
+		tools_default.CreateIndices_historical_constituentsTool(cfg),
+		tools_default.CreateEtfs_profileTool(cfg),
 		tools_default.CreateEtfs_holdingsTool(cfg),
+		tools_default.CreateEtfs_sector_exposureTool(cfg),
+		tools_default.CreateEtfs_country_exposureTool(cfg),
+		tools_default.CreateMutual_fund_profileTool(cfg),
+		tools_default.CreateMutual_fund_holdingsTool(cfg),
+		tools_default.CreateMutual_fund_sector_exposureTool(cfg),
+		tools_default.CreateMutual_fund_country_exposureTool(cfg),
+		tools_default.CreateMutual_fund_eetTool(cfg),
 		tools_default.CreateMutual_fund_eet_paiTool(cfg),
-		tools_default.CreateTranscripts_listTool(cfg),
-		tools_default.CreateCrypto_profileTool(cfg),
-		tools_default.CreateCompany_newsTool(cfg),
-		tools_default.CreateInstitutional_profileTool(cfg),
-		tools_default.CreateCompany_earnings_quality_scoreTool(cfg),
-		tools_default.CreateBank_branchTool(cfg),
+		tools_default.CreateBond_profileTool(cfg),
+		tools_default.CreateBond_priceTool(cfg),
+		tools_default.CreateBond_tickTool(cfg),
 		tools_default.CreateBond_yield_curveTool(cfg),
-		tools_default.CreateStock_usa_spendingTool(cfg),
-		tools_default.CreateStock_lobbyingTool(cfg),
-		tools_default.CreateCompany_basic_financialsTool(cfg),
+		tools_default.CreateForex_exchangesTool(cfg),
+		tools_default.CreateForex_symbolsTool(cfg),
+		tools_default.CreateForex_candlesTool(cfg),
+		tools_default.CreateForex_ratesTool(cfg),
+		tools_default.CreateCrypto_exchangesTool(cfg),
+		tools_default.CreateCrypto_symbolsTool(cfg),
+		tools_default.CreateCrypto_profileTool(cfg),
+		tools_default.CreateCrypto_candlesTool(cfg),
+		tools_default.CreatePattern_recognitionTool(cfg),
+		tools_default.CreateSupport_resistanceTool(cfg),
+		tools_default.CreateAggregate_indicatorTool(cfg),
+		tools_default.CreateTechnical_indicatorTool(cfg),
+		tools_default.CreateTranscripts_listTool(cfg),
+		tools_default.CreateTranscriptsTool(cfg),
+		tools_default.CreateEarnings_call_liveTool(cfg),
+		tools_default.CreateStock_presentationTool(cfg),
+		tools_default.CreateSocial_sentimentTool(cfg),
+		tools_default.CreateInvestment_themesTool(cfg),
+		tools_default.CreateSupply_chain_relationshipsTool(cfg),
+		tools_default.CreateCompany_esg_scoreTool(cfg),
 		tools_default.CreateCompany_historical_esg_scoreTool(cfg),
-		tools_default.CreateOwnershipTool(cfg),
+		tools_default.CreateCompany_earnings_quality_scoreTool(cfg),
 		tools_default.CreateStock_uspto_patentTool(cfg),
-		tools_default.CreateSocial_sentimentTool(cfg),
-		tools_default.CreateMutual_fund_profileTool(cfg),
-		tools_default.CreateEtfs_sector_exposureTool(cfg),
+		tools_default.CreateStock_visa_applicationTool(cfg),
+		tools_default.CreateStock_lobbyingTool(cfg),
+		tools_default.CreateStock_usa_spendingTool(cfg),
+		tools_default.CreateCongressional_tradingTool(cfg),
+		tools_default.CreateBank_branchTool(cfg),
+		tools_default.CreateFda_committee_meeting_calendarTool(cfg),
+		tools_default.CreateAi_chatTool(cfg),
+		tools_default.CreateRevenue_breakdown2Tool(cfg),
+		tools_default.CreateInternational_filingsTool(cfg),
+		tools_default.CreateGlobal_filings_searchTool(cfg),
 		tools_default.CreateSearch_in_filingTool(cfg),
-		tools_default.CreateSupply_chain_relationshipsTool(cfg),
-		tools_default.CreateMutual_fund_sector_exposureTool(cfg),
-		tools_default.CreateStock_presentationTool(cfg),
-		tools_default.CreateFund_ownershipTool(cfg),
-		tools_default.CreateHistorical_market_capTool(cfg),
-		tools_default.CreateCompany_profileTool(cfg),
-		tools_default.CreateRevenue_breakdownTool(cfg),
-		tools_default.CreateCompany_ebit_estimatesTool(cfg),
-		tools_default.CreateFinancials_reportedTool(cfg),
-		tools_default.CreateIndices_constituentsTool(cfg),
-		tools_default.CreateMutual_fund_eetTool(cfg),
-		tools_default.CreateCompany_revenue_estimatesTool(cfg),
-		tools_default.CreateMarket_newsTool(cfg),
-		tools_default.CreateIndices_historical_constituentsTool(cfg),
+		tools_default.CreateGlobal_filings_search_filterTool(cfg),
+		tools_default.CreateGlobal_filings_downloadTool(cfg),
+		tools_default.CreateCountryTool(cfg),
+		tools_default.CreateEconomic_calendarTool(cfg),
+		tools_default.CreateEconomic_codeTool(cfg),
+		tools_default.CreateEconomic_dataTool(cfg),
+		tools_default.CreateAirline_price_indexTool(cfg),
+		tools_default.CreateCovid_19Tool(cfg),
 	}
 }
+
+// Toolsets lists the tool groups, one per section of the Finnhub API
+// documentation.
+var Toolsets = []models.Toolset{
+	{Name: "stock-fundamentals", Title: "Stock Fundamentals"},
+	{Name: "stock-estimates", Title: "Stock Estimates"},
+	{Name: "stock-price", Title: "Stock Price"},
+	{Name: "etfs-indices", Title: "ETFs & Indices"},
+	{Name: "mutual-funds", Title: "Mutual Funds"},
+	{Name: "bonds", Title: "Bonds"},
+	{Name: "forex", Title: "Forex"},
+	{Name: "crypto", Title: "Crypto"},
+	{Name: "technical-analysis", Title: "Technical Analysis"},
+	{Name: "alternative-data", Title: "Alternative Data"},
+	{Name: "enterprise-data", Title: "Enterprise data"},
+	{Name: "global-filings-search", Title: "Global Filings Search"},
+	{Name: "economic", Title: "Economic"},
+}
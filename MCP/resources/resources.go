@@ -0,0 +1,137 @@
+// Package resources exposes Finnhub data as MCP resources, so clients can
+// attach company context to a conversation without a tool call. Every
+// resource template is backed by a tool: reading a resource calls the tool
+// with the variables of the URI as arguments.
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const mimeJSON = "application/json"
+
+// Template is a resource template and the tool that backs it.
+type Template struct {
+	Definition mcp.ResourceTemplate
+	Tool       string // Name of the backing tool, e.g. get_quote
+
+	// list splits the tool result into one resource per item, found under
+	// this key, each identified by the URI built by itemURI.
+	list    string
+	itemURI func(item map[string]any) (string, bool)
+}
+
+// Templates lists the resource templates the server offers.
+func Templates() []Template {
+	return []Template{
+		{
+			Definition: mcp.NewResourceTemplate("finnhub://stock/{symbol}/profile", "Company profile",
+				mcp.WithTemplateDescription("General information about a company: name, exchange, industry, market capitalization, shares outstanding, logo and website."),
+				mcp.WithTemplateMIMEType(mimeJSON)),
+			Tool: "get_stock_profile2",
+		},
+		{
+			Definition: mcp.NewResourceTemplate("finnhub://stock/{symbol}/quote", "Quote",
+				mcp.WithTemplateDescription("Real-time quote of a stock: current, open, high, low and previous close price, and the change since the previous close."),
+				mcp.WithTemplateMIMEType(mimeJSON)),
+			Tool: "get_quote",
+		},
+		{
+			Definition: mcp.NewResourceTemplate("finnhub://stock/{symbol}/transcripts", "Earnings call transcripts",
+				mcp.WithTemplateDescription("Recent earnings call transcripts of a company, one finnhub://transcript/{id} resource per call with its title, year and quarter."),
+				mcp.WithTemplateMIMEType(mimeJSON)),
+			Tool: "get_stock_transcripts_list",
+			list: "transcripts",
+			itemURI: func(item map[string]any) (string, bool) {
+				id, ok := item["id"].(string)
+				return "finnhub://transcript/" + id, ok && id != ""
+			},
+		},
+		{
+			Definition: mcp.NewResourceTemplate("finnhub://transcript/{id}", "Earnings call transcript",
+				mcp.WithTemplateDescription("Full earnings call transcript with its participants. Find transcript IDs under finnhub://stock/{symbol}/transcripts."),
+				mcp.WithTemplateMIMEType(mimeJSON)),
+			Tool: "get_stock_transcripts",
+		},
+		{
+			Definition: mcp.NewResourceTemplate("finnhub://filing/{accessNumber}", "SEC filing",
+				mcp.WithTemplateDescription("Metadata of an SEC filing identified by its access number: form, filing and acceptance dates, and links to the report."),
+				mcp.WithTemplateMIMEType(mimeJSON)),
+			Tool: "get_stock_filings",
+		},
+	}
+}
+
+// Handler reads resources of t by calling its tool through call.
+func (t Template) Handler(call server.ToolHandlerFunc) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		args := make(map[string]any, len(request.Params.Arguments))
+		for name, value := range request.Params.Arguments {
+			// URI variables come back as a list of values
+			if values, ok := value.([]string); ok && len(values) > 0 {
+				value = values[0]
+			}
+			args[name] = value
+		}
+		callRequest := mcp.CallToolRequest{}
+		callRequest.Params.Name = t.Tool
+		callRequest.Params.Arguments = args
+		res, err := call(ctx, callRequest)
+		if err != nil {
+			return nil, err
+		}
+		if res.IsError {
+			return nil, errors.New(text(res))
+		}
+		if t.list != "" {
+			return t.items(request.Params.URI, res)
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: mimeJSON,
+			Text:     text(res),
+		}}, nil
+	}
+}
+
+// items returns one resource per item of a list result.
+func (t Template) items(uri string, res *mcp.CallToolResult) ([]mcp.ResourceContents, error) {
+	structured, _ := res.StructuredContent.(map[string]any)
+	list, _ := structured[t.list].([]any)
+	contents := make([]mcp.ResourceContents, 0, len(list))
+	for _, v := range list {
+		item, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		itemURI, ok := t.itemURI(item)
+		if !ok {
+			continue
+		}
+		data, err := json.MarshalIndent(item, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", itemURI, err)
+		}
+		contents = append(contents, mcp.TextResourceContents{URI: itemURI, MIMEType: mimeJSON, Text: string(data)})
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("no %s found for %s", t.list, uri)
+	}
+	return contents, nil
+}
+
+// text returns the text content of a tool result.
+func text(res *mcp.CallToolResult) string {
+	for _, c := range res.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			return tc.Text
+		}
+	}
+	return ""
+}
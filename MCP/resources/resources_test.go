@@ -0,0 +1,115 @@
+package resources
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// template returns the template backed by tool.
+func template(t *testing.T, tool string) Template {
+	t.Helper()
+	for _, tmpl := range Templates() {
+		if tmpl.Tool == tool {
+			return tmpl
+		}
+	}
+	t.Fatalf("no template for %s", tool)
+	return Template{}
+}
+
+// read reads uri with a handler whose tool call returns body.
+func read(t *testing.T, tmpl Template, uri string, args map[string]any, body string) ([]mcp.ResourceContents, map[string]any, error) {
+	t.Helper()
+	var got map[string]any
+	call := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Name != tmpl.Tool {
+			t.Errorf("called %s, want %s", request.Params.Name, tmpl.Tool)
+		}
+		got = request.GetArguments()
+		return result.JSON([]byte(body)), nil
+	}
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	request.Params.Arguments = args
+	contents, err := tmpl.Handler(call)(context.Background(), request)
+	return contents, got, err
+}
+
+func TestHandler(t *testing.T) {
+	contents, args, err := read(t, template(t, "get_quote"), "finnhub://stock/AAPL/quote",
+		map[string]any{"symbol": []string{"AAPL"}}, `{"c":189.5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args["symbol"] != "AAPL" {
+		t.Errorf("symbol argument = %#v, want \"AAPL\"", args["symbol"])
+	}
+	if len(contents) != 1 {
+		t.Fatalf("got %d resources, want 1", len(contents))
+	}
+	c := contents[0].(mcp.TextResourceContents)
+	if c.URI != "finnhub://stock/AAPL/quote" || c.MIMEType != mimeJSON || !strings.Contains(c.Text, "189.5") {
+		t.Errorf("resource = %+v", c)
+	}
+}
+
+func TestHandlerItems(t *testing.T) {
+	tmpl := template(t, "get_stock_transcripts_list")
+	body := `{"symbol":"AAPL","transcripts":[
+		{"id":"AAPL_162777","title":"AAPL - Earnings Call Transcript Q4 2020","year":2020,"quarter":4},
+		{"title":"no id"},
+		{"id":""},
+		"not an object",
+		{"id":"AAPL_162778","title":"AAPL - Earnings Call Transcript Q1 2021","year":2021,"quarter":1}
+	]}`
+	contents, _, err := read(t, tmpl, "finnhub://stock/AAPL/transcripts", map[string]any{"symbol": []string{"AAPL"}}, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uris []string
+	for _, c := range contents {
+		tc := c.(mcp.TextResourceContents)
+		uris = append(uris, tc.URI)
+		if tc.MIMEType != mimeJSON || !strings.Contains(tc.Text, `"title"`) {
+			t.Errorf("resource %s = %+v", tc.URI, tc)
+		}
+	}
+	want := []string{"finnhub://transcript/AAPL_162777", "finnhub://transcript/AAPL_162778"}
+	if !slices.Equal(uris, want) {
+		t.Errorf("resources %q, want %q", uris, want)
+	}
+}
+
+func TestHandlerEmptyList(t *testing.T) {
+	tmpl := template(t, "get_stock_transcripts_list")
+	for name, body := range map[string]string{
+		"empty":    `{"symbol":"XYZ","transcripts":[]}`,
+		"missing":  `{"symbol":"XYZ"}`,
+		"no items": `{"symbol":"XYZ","transcripts":[{"title":"no id"}]}`,
+		"not JSON": `not found`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := read(t, tmpl, "finnhub://stock/XYZ/transcripts", nil, body)
+			if err == nil || !strings.Contains(err.Error(), "no transcripts found for finnhub://stock/XYZ/transcripts") {
+				t.Errorf("error = %v, want no transcripts found", err)
+			}
+		})
+	}
+}
+
+func TestHandlerToolError(t *testing.T) {
+	tmpl := template(t, "get_quote")
+	call := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("symbol not found"), nil
+	}
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "finnhub://stock/XYZ/quote"
+	if _, err := tmpl.Handler(call)(context.Background(), request); err == nil || err.Error() != "symbol not found" {
+		t.Errorf("error = %v, want the tool's error", err)
+	}
+}
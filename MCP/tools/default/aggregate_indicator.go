@@ -1,82 +1,106 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// aggregateIndicatorAccess is the plan access get_scan_technical-indicator requires, from swagger.json.
+var aggregateIndicatorAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// aggregateIndicatorsOutputSchema is the output schema for AggregateIndicators, derived from swagger.json.
+const aggregateIndicatorsOutputSchema = `{
+  "properties": {
+    "technicalAnalysis": {
+      "properties": {
+        "count": {
+          "properties": {
+            "buy": {
+              "description": "Number of buy signals",
+              "type": ["integer", "null"]
+            },
+            "neutral": {
+              "description": "Number of neutral signals",
+              "type": ["integer", "null"]
+            },
+            "sell": {
+              "description": "Number of sell signals",
+              "type": ["integer", "null"]
+            }
+          },
+          "type": ["object", "null"]
+        },
+        "signal": {
+          "description": "Aggregate Signal",
+          "type": ["string", "null"]
+        }
+      },
+      "type": ["object", "null"]
+    },
+    "trend": {
+      "properties": {
+        "adx": {
+          "description": "ADX reading",
+          "type": ["number", "null"]
+        },
+        "trending": {
+          "description": "Whether market is trending or going sideway",
+          "type": ["boolean", "null"]
+        }
+      },
+      "type": ["object", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Aggregate_indicatorHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["resolution"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("resolution=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/scan/technical-indicator%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "resolution")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_scan_technical-indicator", Method: "GET", Path: "/scan/technical-indicator", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_scan_technical-indicator", aggregateIndicatorAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateAggregate_indicatorTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_scan_technical-indicator",
-		mcp.WithDescription("Aggregate Indicators"),
+		mcp.WithDescription("Get aggregate signal of multiple technical indicators such as MACD, RSI, Moving Average v.v. A full list of indicators can be found [here](https://docs.google.com/spreadsheets/d/1MWuy0WuT2yVlxr1KbPdggVygMZtJfunDnhe-C0GEXYM/edit?usp=sharing)."),
+		mcp.WithTitleAnnotation("Aggregate Indicators"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(aggregateIndicatorsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("symbol")),
-		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes <code>1, 5, 15, 30, 60, D, W, M </code>.Some timeframes might not be available depending on the exchange.")),
+		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes `1, 5, 15, 30, 60, D, W, M `.Some timeframes might not be available depending on the exchange."), mcp.Enum("1", "5", "15", "30", "60", "D", "W", "M")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Aggregate_indicatorHandler(cfg),
+		Handler:    schema.Checked(tool, Aggregate_indicatorHandler(cfg)),
+		Toolset:    "technical-analysis",
+		Access:     aggregateIndicatorAccess,
 	}
 }
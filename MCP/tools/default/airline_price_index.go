@@ -1,86 +1,101 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// airlinePriceIndexAccess is the plan access get_airline_price-index requires, from swagger.json.
+var airlinePriceIndexAccess = plan.Access{Tier: plan.Fundamental, Note: "Accessible with Fundamental data or All in One subscription."}
+
+// airlinePriceIndexDataOutputSchema is the output schema for AirlinePriceIndexData, derived from swagger.json.
+const airlinePriceIndexDataOutputSchema = `{
+  "properties": {
+    "airline": {
+      "description": "Airline name",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of price index.",
+      "items": {
+        "properties": {
+          "dailyAvgPrice": {
+            "description": "Daily average ticket price.",
+            "type": ["number", "null"]
+          },
+          "date": {
+            "description": "Date",
+            "type": ["string", "null"]
+          },
+          "priceIndex": {
+            "description": "Price Index",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "from": {
+      "description": "From date",
+      "type": ["string", "null"]
+    },
+    "to": {
+      "description": "To date",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Airline_price_indexHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["airline"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("airline=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/airline/price-index%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "airline", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_airline_price-index", Method: "GET", Path: "/airline/price-index", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_airline_price-index", airlinePriceIndexAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateAirline_price_indexTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_airline_price-index",
-		mcp.WithDescription("Airline Price Index"),
-		mcp.WithString("airline", mcp.Required(), mcp.Description("Filter data by airline. Accepted values: <code>united</code>,<code>delta</code>,<code>american_airlines</code>,<code>southwest</code>,<code>southern_airways_express</code>,<code>alaska_airlines</code>,<code>frontier_airlines</code>,<code>jetblue_airways</code>,<code>spirit_airlines</code>,<code>sun_country_airlines</code>,<code>breeze_airways</code>,<code>hawaiian_airlines</code>")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("From date <code>YYYY-MM-DD</code>.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("To date <code>YYYY-MM-DD</code>.")),
+		mcp.WithDescription("The Flight Ticket Price Index API provides comprehensive data on airline ticket prices, including the average daily ticket price and its percentage change (price index). This data, collected weekly and projected two weeks ahead, aggregates daily prices and indexes from the 50 busiest and largest airports across the USA. The dataset includes detailed information on airlines, dates, and average ticket prices, offering valuable insights for market analysis and pricing strategies.\n\nThe price index is calculated as percentage change of average daily ticket price from the previous weekly reading. Raw ticket prices data is available for Enterprise users. [Contact us](mailto:support@finnhub.io) to inquire about the raw price data."),
+		mcp.WithTitleAnnotation("Airline Price Index"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(airlinePriceIndexDataOutputSchema)),
+		mcp.WithString("airline", mcp.Required(), mcp.Description("Filter data by airline. Accepted values: `united`,`delta`,`american_airlines`,`southwest`,`southern_airways_express`,`alaska_airlines`,`frontier_airlines`,`jetblue_airways`,`spirit_airlines`,`sun_country_airlines`,`breeze_airways`,`hawaiian_airlines`"), mcp.Enum("united", "delta", "american_airlines", "southwest", "southern_airways_express", "alaska_airlines", "frontier_airlines", "jetblue_airways", "spirit_airlines", "sun_country_airlines", "breeze_airways", "hawaiian_airlines")),
+		mcp.WithString("from", mcp.Required(), mcp.Description("From date `YYYY-MM-DD`."), schema.Format("date")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("To date `YYYY-MM-DD`."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Airline_price_indexHandler(cfg),
+		Handler:    schema.Checked(tool, Airline_price_indexHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     airlinePriceIndexAccess,
 	}
 }
@@ -1,78 +1,99 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// bankBranchAccess is the plan access get_bank-branch requires, from swagger.json.
+var bankBranchAccess = plan.Access{Tier: plan.Fundamental, Note: "Accessible with Fundamental or All in One subscription."}
+
+// bankBranchResOutputSchema is the output schema for BankBranchRes, derived from swagger.json.
+const bankBranchResOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Array of branches.",
+      "items": {
+        "properties": {
+          "address": {
+            "description": "Branch address",
+            "type": ["string", "null"]
+          },
+          "branchId": {
+            "description": "Branch ID",
+            "type": ["string", "null"]
+          },
+          "date": {
+            "description": "Date opened",
+            "type": ["string", "null"]
+          },
+          "state": {
+            "description": "State",
+            "type": ["string", "null"]
+          },
+          "zipCode": {
+            "description": "Zip code",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Bank_branchHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/bank-branch%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_bank-branch", Method: "GET", Path: "/bank-branch", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_bank-branch", bankBranchAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateBank_branchTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_bank-branch",
-		mcp.WithDescription("Bank Branch List"),
+		mcp.WithDescription("Retrieve list of US bank branches information for a given symbol."),
+		mcp.WithTitleAnnotation("Bank Branch List"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(bankBranchResOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Bank_branchHandler(cfg),
+		Handler:    schema.Checked(tool, Bank_branchHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     bankBranchAccess,
 	}
 }
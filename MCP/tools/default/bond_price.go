@@ -1,86 +1,86 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// bondPriceAccess is the plan access get_bond_price requires, from swagger.json.
+var bondPriceAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// bondCandlesOutputSchema is the output schema for BondCandles, derived from swagger.json.
+const bondCandlesOutputSchema = `{
+  "properties": {
+    "c": {
+      "description": "List of close prices for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "s": {
+      "description": "Status of the response. This field can either be ok or no_data.",
+      "type": ["string", "null"]
+    },
+    "t": {
+      "description": "List of timestamp for returned candles.",
+      "items": {
+        "type": ["integer", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Bond_priceHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["isin"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("isin=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/bond/price%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "isin", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_bond_price", Method: "GET", Path: "/bond/price", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_bond_price", bondPriceAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateBond_priceTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_bond_price",
-		mcp.WithDescription("Bond price data"),
+		mcp.WithDescription("Get bond's price data. The following datasets are supported:\n\n| Exchange | Segment | Delay |\n| --- | --- | --- |\n| US Government Bonds | Government Bonds | End-of-day |\n| FINRA Trace | BTDS: US Corporate Bonds | Delayed 4h |\n| FINRA Trace | 144A Bonds | Delayed 4h |"),
+		mcp.WithTitleAnnotation("Bond price data"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(bondCandlesOutputSchema)),
 		mcp.WithString("isin", mcp.Required(), mcp.Description("ISIN.")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("UNIX timestamp. Interval initial value.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("UNIX timestamp. Interval end value.")),
+		mcp.WithNumber("from", schema.Integer(), mcp.Required(), mcp.Description("UNIX timestamp. Interval initial value.")),
+		mcp.WithNumber("to", schema.Integer(), mcp.Required(), mcp.Description("UNIX timestamp. Interval end value.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Bond_priceHandler(cfg),
+		Handler:    schema.Checked(tool, Bond_priceHandler(cfg)),
+		Toolset:    "bonds",
+		Access:     bondPriceAccess,
 	}
 }
@@ -1,78 +1,87 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// bondYieldCurveAccess is the plan access get_bond_yield-curve requires, from swagger.json.
+var bondYieldCurveAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// bondYieldCurveOutputSchema is the output schema for BondYieldCurve, derived from swagger.json.
+const bondYieldCurveOutputSchema = `{
+  "properties": {
+    "code": {
+      "description": "Bond's code",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of data.",
+      "items": {
+        "properties": {
+          "d": {
+            "description": "Date of the reading",
+            "type": ["string", "null"]
+          },
+          "v": {
+            "description": "Value",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Bond_yield_curveHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["code"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("code=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/bond/yield-curve%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "code")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_bond_yield-curve", Method: "GET", Path: "/bond/yield-curve", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_bond_yield-curve", bondYieldCurveAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateBond_yield_curveTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_bond_yield-curve",
-		mcp.WithDescription("Bond Yield Curve"),
-		mcp.WithString("code", mcp.Required(), mcp.Description("Bond's code. You can find the list of supported code <a href=\"https://docs.google.com/spreadsheets/d/1iA-lM0Kht7lsQZ7Uu_s6r2i1BbQNUNO9eGkO5-zglHg/edit?usp=sharing\" target=\"_blank\" rel=\"noopener\">here</a>.")),
+		mcp.WithDescription("Get yield curve data for Treasury bonds."),
+		mcp.WithTitleAnnotation("Bond Yield Curve"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(bondYieldCurveOutputSchema)),
+		mcp.WithString("code", mcp.Required(), mcp.Description("Bond's code. You can find the list of supported code [here](https://docs.google.com/spreadsheets/d/1iA-lM0Kht7lsQZ7Uu_s6r2i1BbQNUNO9eGkO5-zglHg/edit?usp=sharing).")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Bond_yield_curveHandler(cfg),
+		Handler:    schema.Checked(tool, Bond_yield_curveHandler(cfg)),
+		Toolset:    "bonds",
+		Access:     bondYieldCurveAccess,
 	}
 }
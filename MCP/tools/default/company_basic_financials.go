@@ -1,82 +1,81 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// companyBasicFinancialsAccess is the plan access get_stock_metric requires, from swagger.json.
+var companyBasicFinancialsAccess = plan.Access{Tier: plan.Free}
+
+// basicFinancialsOutputSchema is the output schema for BasicFinancials, derived from swagger.json.
+const basicFinancialsOutputSchema = `{
+  "properties": {
+    "metric": {
+      "type": ["object", "null"]
+    },
+    "metricType": {
+      "description": "Metric type.",
+      "type": ["string", "null"]
+    },
+    "series": {
+      "type": ["object", "null"]
+    },
+    "symbol": {
+      "description": "Symbol of the company.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Company_basic_financialsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["metric"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("metric=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/metric%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "metric")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_metric", Method: "GET", Path: "/stock/metric", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_metric", companyBasicFinancialsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCompany_basic_financialsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_metric",
-		mcp.WithDescription("Basic Financials"),
+		mcp.WithDescription("Get company basic financials such as margin, P/E ratio, 52-week high/low etc."),
+		mcp.WithTitleAnnotation("Basic Financials"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(basicFinancialsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
-		mcp.WithString("metric", mcp.Required(), mcp.Description("Metric type. Can be 1 of the following values <code>all</code>")),
+		mcp.WithString("metric", mcp.Required(), mcp.Description("Metric type. Can be 1 of the following values `all`"), mcp.Enum("all")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Company_basic_financialsHandler(cfg),
+		Handler:    schema.Checked(tool, Company_basic_financialsHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     companyBasicFinancialsAccess,
 	}
 }
@@ -1,78 +1,89 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// companyEsgScoreAccess is the plan access get_stock_esg requires, from swagger.json.
+var companyEsgScoreAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// companyESGOutputSchema is the output schema for CompanyESG, derived from swagger.json.
+const companyESGOutputSchema = `{
+  "properties": {
+    "data": {
+      "type": ["object", "null"]
+    },
+    "environmentScore": {
+      "description": "Environment Score",
+      "type": ["number", "null"]
+    },
+    "governanceScore": {
+      "description": "Governance Score",
+      "type": ["number", "null"]
+    },
+    "socialScore": {
+      "description": "Social Score",
+      "type": ["number", "null"]
+    },
+    "symbol": {
+      "description": "symbol",
+      "type": ["string", "null"]
+    },
+    "totalESGScore": {
+      "description": "Total ESG Score",
+      "type": ["number", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Company_esg_scoreHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/esg%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_esg", Method: "GET", Path: "/stock/esg", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_esg", companyEsgScoreAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCompany_esg_scoreTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_esg",
-		mcp.WithDescription("Company ESG Scores"),
+		mcp.WithDescription("This endpoint provides the latest ESG scores and important indicators for 7000+ global companies. The data is collected through company's public ESG disclosure and public sources.\n\nOur ESG scoring models takes into account more than 150 different inputs to calculate the level of ESG risks and how well a company is managing them. A higher score means lower ESG risk or better ESG management. ESG scores are in the the range of 0-100. Some key indicators might contain letter-grade score from C- to A+ with C- is the lowest score and A+ is the highest score."),
+		mcp.WithTitleAnnotation("Company ESG Scores"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(companyESGOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Company_esg_scoreHandler(cfg),
+		Handler:    schema.Checked(tool, Company_esg_scoreHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     companyEsgScoreAccess,
 	}
 }
@@ -1,78 +1,107 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// companyExecutiveAccess is the plan access get_stock_executive requires, from swagger.json.
+var companyExecutiveAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// companyExecutiveOutputSchema is the output schema for CompanyExecutive, derived from swagger.json.
+const companyExecutiveOutputSchema = `{
+  "properties": {
+    "executive": {
+      "description": "Array of company's executives and members of the Board.",
+      "items": {
+        "properties": {
+          "age": {
+            "description": "Age",
+            "type": ["integer", "null"]
+          },
+          "compensation": {
+            "description": "Total compensation",
+            "type": ["integer", "null"]
+          },
+          "currency": {
+            "description": "Compensation currency",
+            "type": ["string", "null"]
+          },
+          "name": {
+            "description": "Executive name",
+            "type": ["string", "null"]
+          },
+          "sex": {
+            "description": "Sex",
+            "type": ["string", "null"]
+          },
+          "since": {
+            "description": "Year first appointed as executive/director of the company",
+            "type": ["string", "null"]
+          },
+          "title": {
+            "description": "Title",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Company symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Company_executiveHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/executive%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_executive", Method: "GET", Path: "/stock/executive", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_executive", companyExecutiveAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCompany_executiveTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_executive",
-		mcp.WithDescription("Company Executive"),
+		mcp.WithDescription("Get a list of company's executives and members of the Board."),
+		mcp.WithTitleAnnotation("Company Executive"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(companyExecutiveOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Company_executiveHandler(cfg),
+		Handler:    schema.Checked(tool, Company_executiveHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     companyExecutiveAccess,
 	}
 }
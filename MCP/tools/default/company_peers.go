@@ -1,82 +1,73 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// companyPeersAccess is the plan access get_stock_peers requires, from swagger.json.
+var companyPeersAccess = plan.Access{Tier: plan.Free}
+
+// stringListOutputSchema is the output schema for string arrays, derived from swagger.json.
+const stringListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "type": ["string", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Company_peersHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["grouping"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("grouping=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/peers%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "grouping")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_peers", Method: "GET", Path: "/stock/peers", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_peers", companyPeersAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCompany_peersTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_peers",
-		mcp.WithDescription("Peers"),
+		mcp.WithDescription("Get company peers. Return a list of peers operating in the same country and sector/industry."),
+		mcp.WithTitleAnnotation("Peers"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(stringListOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
-		mcp.WithString("grouping", mcp.Description("Specify the grouping criteria for choosing peers.Supporter values: <code>sector</code>, <code>industry</code>, <code>subIndustry</code>. Default to <code>subIndustry</code>.")),
+		mcp.WithString("grouping", mcp.Description("Specify the grouping criteria for choosing peers.Supporter values: `sector`, `industry`, `subIndustry`. Default to `subIndustry`."), mcp.Enum("sector", "industry", "subIndustry")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Company_peersHandler(cfg),
+		Handler:    schema.Checked(tool, Company_peersHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     companyPeersAccess,
 	}
 }
@@ -1,64 +1,115 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// countryAccess is the plan access get_country requires, from swagger.json.
+var countryAccess = plan.Access{Tier: plan.Free}
+
+// countryMetadataListOutputSchema is the output schema for CountryMetadata arrays, derived from swagger.json.
+const countryMetadataListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "code2": {
+            "description": "Alpha 2 code",
+            "type": ["string", "null"]
+          },
+          "code3": {
+            "description": "Alpha 3 code",
+            "type": ["string", "null"]
+          },
+          "codeNo": {
+            "description": "UN code",
+            "type": ["string", "null"]
+          },
+          "country": {
+            "description": "Country name",
+            "type": ["string", "null"]
+          },
+          "countryRiskPremium": {
+            "description": "Country risk premium",
+            "type": ["number", "null"]
+          },
+          "currency": {
+            "description": "Currency name",
+            "type": ["string", "null"]
+          },
+          "currencyCode": {
+            "description": "Currency code",
+            "type": ["string", "null"]
+          },
+          "defaultSpread": {
+            "description": "Default spread",
+            "type": ["number", "null"]
+          },
+          "equityRiskPremium": {
+            "description": "Equity risk premium",
+            "type": ["number", "null"]
+          },
+          "rating": {
+            "description": "Moody's credit risk rating.",
+            "type": ["string", "null"]
+          },
+          "region": {
+            "description": "Region",
+            "type": ["string", "null"]
+          },
+          "subRegion": {
+            "description": "Sub-Region",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func CountryHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/country", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_country", Method: "GET", Path: "/country"})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return result.Error(ctx, cfg, "get_country", countryAccess, resp), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCountryTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_country",
-		mcp.WithDescription("Country Metadata"),
+		mcp.WithDescription("List all countries and metadata."),
+		mcp.WithTitleAnnotation("Country Metadata"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(countryMetadataListOutputSchema)),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    CountryHandler(cfg),
+		Handler:    schema.Checked(tool, CountryHandler(cfg)),
+		Toolset:    "economic",
+		Access:     countryAccess,
 	}
 }
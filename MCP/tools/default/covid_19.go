@@ -1,64 +1,83 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// covid19Access is the plan access get_covid19_us requires, from swagger.json.
+var covid19Access = plan.Access{Tier: plan.Free}
+
+// covidInfoListOutputSchema is the output schema for CovidInfo arrays, derived from swagger.json.
+const covidInfoListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "case": {
+            "description": "Number of confirmed cases.",
+            "type": ["number", "null"]
+          },
+          "death": {
+            "description": "Number of confirmed deaths.",
+            "type": ["number", "null"]
+          },
+          "state": {
+            "description": "State.",
+            "type": ["string", "null"]
+          },
+          "updated": {
+            "description": "Updated time.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Covid_19Handler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/covid19/us", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_covid19_us", Method: "GET", Path: "/covid19/us"})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return result.Error(ctx, cfg, "get_covid19_us", covid19Access, resp), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCovid_19Tool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_covid19_us",
-		mcp.WithDescription("COVID-19"),
+		mcp.WithDescription("Get real-time updates on the number of COVID-19 (Corona virus) cases in the US with a state-by-state breakdown. Data is sourced from CDC and reputable sources. You can also access this API [here](https://rapidapi.com/Finnhub/api/finnhub-real-time-covid-19)"),
+		mcp.WithTitleAnnotation("COVID-19"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(covidInfoListOutputSchema)),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Covid_19Handler(cfg),
+		Handler:    schema.Checked(tool, Covid_19Handler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     covid19Access,
 	}
 }
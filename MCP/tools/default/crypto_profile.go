@@ -1,78 +1,110 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// cryptoProfileAccess is the plan access get_crypto_profile requires, from swagger.json.
+var cryptoProfileAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// cryptoProfileOutputSchema is the output schema for CryptoProfile, derived from swagger.json.
+const cryptoProfileOutputSchema = `{
+  "properties": {
+    "circulatingSupply": {
+      "description": "Circulating supply.",
+      "type": ["number", "null"]
+    },
+    "description": {
+      "description": "Description.",
+      "type": ["string", "null"]
+    },
+    "launchDate": {
+      "description": "Launch date.",
+      "type": ["string", "null"]
+    },
+    "logo": {
+      "description": "Logo image.",
+      "type": ["string", "null"]
+    },
+    "longName": {
+      "description": "Long name.",
+      "type": ["string", "null"]
+    },
+    "marketCap": {
+      "description": "Market capitalization.",
+      "type": ["number", "null"]
+    },
+    "maxSupply": {
+      "description": "Max supply.",
+      "type": ["number", "null"]
+    },
+    "name": {
+      "description": "Name.",
+      "type": ["string", "null"]
+    },
+    "proofType": {
+      "description": "Proof type.",
+      "type": ["string", "null"]
+    },
+    "totalSupply": {
+      "description": "Total supply.",
+      "type": ["number", "null"]
+    },
+    "website": {
+      "description": "Project's website.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Crypto_profileHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/crypto/profile%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_crypto_profile", Method: "GET", Path: "/crypto/profile", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_crypto_profile", cryptoProfileAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateCrypto_profileTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_crypto_profile",
-		mcp.WithDescription("Crypto Profile"),
+		mcp.WithDescription("Get crypto's profile."),
+		mcp.WithTitleAnnotation("Crypto Profile"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(cryptoProfileOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Crypto symbol such as BTC or ETH.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Crypto_profileHandler(cfg),
+		Handler:    schema.Checked(tool, Crypto_profileHandler(cfg)),
+		Toolset:    "crypto",
+		Access:     cryptoProfileAccess,
 	}
 }
@@ -1,82 +1,108 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// economicCalendarAccess is the plan access get_calendar_economic requires, from swagger.json.
+var economicCalendarAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// economicCalendarOutputSchema is the output schema for EconomicCalendar, derived from swagger.json.
+const economicCalendarOutputSchema = `{
+  "properties": {
+    "economicCalendar": {
+      "description": "Array of economic events.",
+      "items": {
+        "properties": {
+          "actual": {
+            "description": "Actual release",
+            "type": ["number", "null"]
+          },
+          "country": {
+            "description": "Country",
+            "type": ["string", "null"]
+          },
+          "estimate": {
+            "description": "Estimate",
+            "type": ["number", "null"]
+          },
+          "event": {
+            "description": "Event",
+            "type": ["string", "null"]
+          },
+          "impact": {
+            "description": "Impact level",
+            "type": ["string", "null"]
+          },
+          "prev": {
+            "description": "Previous release",
+            "type": ["number", "null"]
+          },
+          "time": {
+            "description": "Release time",
+            "type": ["string", "null"]
+          },
+          "unit": {
+            "description": "Unit",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Economic_calendarHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/calendar/economic%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_calendar_economic", Method: "GET", Path: "/calendar/economic", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_calendar_economic", economicCalendarAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateEconomic_calendarTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_calendar_economic",
-		mcp.WithDescription("Economic Calendar"),
-		mcp.WithString("from", mcp.Description("From date <code>YYYY-MM-DD</code>.")),
-		mcp.WithString("to", mcp.Description("To date <code>YYYY-MM-DD</code>.")),
+		mcp.WithDescription("Get recent and upcoming economic releases.\n\nHistorical events and surprises are available for Enterprise clients."),
+		mcp.WithTitleAnnotation("Economic Calendar"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(economicCalendarOutputSchema)),
+		mcp.WithString("from", mcp.Description("From date `YYYY-MM-DD`."), schema.Format("date")),
+		mcp.WithString("to", mcp.Description("To date `YYYY-MM-DD`."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Economic_calendarHandler(cfg),
+		Handler:    schema.Checked(tool, Economic_calendarHandler(cfg)),
+		Toolset:    "economic",
+		Access:     economicCalendarAccess,
 	}
 }
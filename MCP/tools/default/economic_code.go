@@ -1,64 +1,83 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// economicCodeAccess is the plan access get_economic_code requires, from swagger.json.
+var economicCodeAccess = plan.Access{Tier: plan.Fundamental, Note: "Accessible with Fundamental data or All in One subscription."}
+
+// economicCodeListOutputSchema is the output schema for EconomicCode arrays, derived from swagger.json.
+const economicCodeListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "code": {
+            "description": "Finnhub economic code used to get historical data",
+            "type": ["string", "null"]
+          },
+          "country": {
+            "description": "Country",
+            "type": ["string", "null"]
+          },
+          "name": {
+            "description": "Indicator name",
+            "type": ["string", "null"]
+          },
+          "unit": {
+            "description": "Unit",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Economic_codeHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/economic/code", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_economic_code", Method: "GET", Path: "/economic/code"})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return result.Error(ctx, cfg, "get_economic_code", economicCodeAccess, resp), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateEconomic_codeTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_economic_code",
-		mcp.WithDescription("Economic Code"),
+		mcp.WithDescription("List codes of supported economic data."),
+		mcp.WithTitleAnnotation("Economic Code"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(economicCodeListOutputSchema)),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Economic_codeHandler(cfg),
+		Handler:    schema.Checked(tool, Economic_codeHandler(cfg)),
+		Toolset:    "economic",
+		Access:     economicCodeAccess,
 	}
 }
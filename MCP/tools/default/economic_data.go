@@ -1,78 +1,87 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// economicDataAccess is the plan access get_economic requires, from swagger.json.
+var economicDataAccess = plan.Access{Tier: plan.Fundamental, Note: "Accessible with Fundamental data or All in One subscription."}
+
+// economicDataOutputSchema is the output schema for EconomicData, derived from swagger.json.
+const economicDataOutputSchema = `{
+  "properties": {
+    "code": {
+      "description": "Finnhub economic code",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of economic data for requested code.",
+      "items": {
+        "properties": {
+          "date": {
+            "description": "Date of the reading",
+            "type": ["string", "null"]
+          },
+          "value": {
+            "description": "Value",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Economic_dataHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["code"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("code=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/economic%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "code")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_economic", Method: "GET", Path: "/economic", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_economic", economicDataAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateEconomic_dataTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_economic",
-		mcp.WithDescription("Economic Data"),
+		mcp.WithDescription("Get economic data."),
+		mcp.WithTitleAnnotation("Economic Data"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(economicDataOutputSchema)),
 		mcp.WithString("code", mcp.Required(), mcp.Description("Economic code.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Economic_dataHandler(cfg),
+		Handler:    schema.Checked(tool, Economic_dataHandler(cfg)),
+		Toolset:    "economic",
+		Access:     economicDataAccess,
 	}
 }
@@ -1,82 +1,88 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// etfsCountryExposureAccess is the plan access get_etf_country requires, from swagger.json.
+var etfsCountryExposureAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// etFsCountryExposureOutputSchema is the output schema for ETFsCountryExposure, derived from swagger.json.
+const etFsCountryExposureOutputSchema = `{
+  "properties": {
+    "countryExposure": {
+      "description": "Array of countries and and exposure levels.",
+      "items": {
+        "properties": {
+          "country": {
+            "description": "Country",
+            "type": ["string", "null"]
+          },
+          "exposure": {
+            "description": "Percent of exposure.",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "ETF symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Etfs_country_exposureHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["isin"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("isin=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/etf/country%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "isin")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_etf_country", Method: "GET", Path: "/etf/country", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_etf_country", etfsCountryExposureAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateEtfs_country_exposureTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_etf_country",
-		mcp.WithDescription("ETFs Country Exposure"),
+		mcp.WithDescription("Get ETF country exposure data."),
+		mcp.WithTitleAnnotation("ETFs Country Exposure"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(etFsCountryExposureOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("ETF symbol.")),
 		mcp.WithString("isin", mcp.Description("ETF isin.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Etfs_country_exposureHandler(cfg),
+		Handler:    schema.Checked(tool, Etfs_country_exposureHandler(cfg)),
+		Toolset:    "etfs-indices",
+		Access:     etfsCountryExposureAccess,
 	}
 }
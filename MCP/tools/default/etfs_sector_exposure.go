@@ -1,82 +1,88 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// etfsSectorExposureAccess is the plan access get_etf_sector requires, from swagger.json.
+var etfsSectorExposureAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// etFsSectorExposureOutputSchema is the output schema for ETFsSectorExposure, derived from swagger.json.
+const etFsSectorExposureOutputSchema = `{
+  "properties": {
+    "sectorExposure": {
+      "description": "Array of industries and exposure levels.",
+      "items": {
+        "properties": {
+          "exposure": {
+            "description": "Percent of exposure.",
+            "type": ["number", "null"]
+          },
+          "industry": {
+            "description": "Industry",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "ETF symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Etfs_sector_exposureHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["isin"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("isin=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/etf/sector%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "isin")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_etf_sector", Method: "GET", Path: "/etf/sector", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_etf_sector", etfsSectorExposureAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateEtfs_sector_exposureTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_etf_sector",
-		mcp.WithDescription("ETFs Sector Exposure"),
+		mcp.WithDescription("Get ETF sector exposure data."),
+		mcp.WithTitleAnnotation("ETFs Sector Exposure"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(etFsSectorExposureOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("ETF symbol.")),
 		mcp.WithString("isin", mcp.Description("ETF isin.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Etfs_sector_exposureHandler(cfg),
+		Handler:    schema.Checked(tool, Etfs_sector_exposureHandler(cfg)),
+		Toolset:    "etfs-indices",
+		Access:     etfsSectorExposureAccess,
 	}
 }
@@ -1,64 +1,83 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// fdaCommitteeMeetingCalendarAccess is the plan access get_fda-advisory-committee-calendar requires, from swagger.json.
+var fdaCommitteeMeetingCalendarAccess = plan.Access{Tier: plan.Free}
+
+// fdaComitteeMeetingListOutputSchema is the output schema for FDAComitteeMeeting arrays, derived from swagger.json.
+const fdaComitteeMeetingListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "eventDescription": {
+            "description": "Event's description.",
+            "type": ["string", "null"]
+          },
+          "fromDate": {
+            "description": "Start time of the event in EST.",
+            "type": ["string", "null"]
+          },
+          "toDate": {
+            "description": "End time of the event in EST.",
+            "type": ["string", "null"]
+          },
+          "url": {
+            "description": "URL.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Fda_committee_meeting_calendarHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/fda-advisory-committee-calendar", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_fda-advisory-committee-calendar", Method: "GET", Path: "/fda-advisory-committee-calendar"})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return result.Error(ctx, cfg, "get_fda-advisory-committee-calendar", fdaCommitteeMeetingCalendarAccess, resp), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateFda_committee_meeting_calendarTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_fda-advisory-committee-calendar",
-		mcp.WithDescription("FDA Committee Meeting Calendar"),
+		mcp.WithDescription("FDA's advisory committees are established to provide functions which support the agency's mission of protecting and promoting the public health, while meeting the requirements set forth in the Federal Advisory Committee Act. Committees are either mandated by statute or established at the discretion of the Department of Health and Human Services. Each committee is subject to renewal at two-year intervals unless the committee charter states otherwise."),
+		mcp.WithTitleAnnotation("FDA Committee Meeting Calendar"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(fdaComitteeMeetingListOutputSchema)),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Fda_committee_meeting_calendarHandler(cfg),
+		Handler:    schema.Checked(tool, Fda_committee_meeting_calendarHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     fdaCommitteeMeetingCalendarAccess,
 	}
 }
@@ -1,86 +1,79 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// financialsAccess is the plan access get_stock_financials requires, from swagger.json.
+var financialsAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// financialStatementsOutputSchema is the output schema for FinancialStatements, derived from swagger.json.
+const financialStatementsOutputSchema = `{
+  "properties": {
+    "financials": {
+      "description": "An array of map of key, value pairs containing the data for each period.",
+      "items": {
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol of the company.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func FinancialsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["statement"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("statement=%v", val))
-		}
-		if val, ok := args["freq"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("freq=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/financials%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "statement", "freq")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_financials", Method: "GET", Path: "/stock/financials", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_financials", financialsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateFinancialsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_financials",
-		mcp.WithDescription("Financial Statements"),
+		mcp.WithDescription("Get standardized balance sheet, income statement and cash flow for global companies going back 30+ years. Data is sourced from original filings most of which made available through [SEC Filings](https://finnhub.io/docs/api/filings) and [International Filings](https://finnhub.io/docs/api/international-filings) endpoints.\n\n*Wondering why our standardized data is different from Bloomberg, Reuters, Factset, S&P or Yahoo Finance ? Check out our [FAQ page](https://finnhub.io/faq) to learn more*"),
+		mcp.WithTitleAnnotation("Financial Statements"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(financialStatementsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
-		mcp.WithString("statement", mcp.Required(), mcp.Description("Statement can take 1 of these values <code>bs, ic, cf</code> for Balance Sheet, Income Statement, Cash Flow respectively.")),
-		mcp.WithString("freq", mcp.Required(), mcp.Description("Frequency can take 1 of these values <code>annual, quarterly, ttm, ytd</code>.  TTM (Trailing Twelve Months) option is available for Income Statement and Cash Flow. YTD (Year To Date) option is only available for Cash Flow.")),
+		mcp.WithString("statement", mcp.Required(), mcp.Description("Statement can take 1 of these values `bs, ic, cf` for Balance Sheet, Income Statement, Cash Flow respectively."), mcp.Enum("bs", "ic", "cf")),
+		mcp.WithString("freq", mcp.Required(), mcp.Description("Frequency can take 1 of these values `annual, quarterly, ttm, ytd`. TTM (Trailing Twelve Months) option is available for Income Statement and Cash Flow. YTD (Year To Date) option is only available for Cash Flow."), mcp.Enum("annual", "quarterly", "ttm", "ytd")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    FinancialsHandler(cfg),
+		Handler:    schema.Checked(tool, FinancialsHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     financialsAccess,
 	}
 }
@@ -1,90 +1,115 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// forexCandlesAccess is the plan access get_forex_candle requires, from swagger.json.
+var forexCandlesAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// forexCandlesOutputSchema is the output schema for ForexCandles, derived from swagger.json.
+const forexCandlesOutputSchema = `{
+  "properties": {
+    "c": {
+      "description": "List of close prices for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "h": {
+      "description": "List of high prices for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "l": {
+      "description": "List of low prices for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "o": {
+      "description": "List of open prices for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "s": {
+      "description": "Status of the response. This field can either be ok or no_data.",
+      "type": ["string", "null"]
+    },
+    "t": {
+      "description": "List of timestamp for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "v": {
+      "description": "List of volume data for returned candles.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Forex_candlesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["resolution"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("resolution=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/forex/candle%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "resolution", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_forex_candle", Method: "GET", Path: "/forex/candle", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_forex_candle", forexCandlesAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateForex_candlesTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_forex_candle",
-		mcp.WithDescription("Forex Candles"),
-		mcp.WithString("symbol", mcp.Required(), mcp.Description("Use symbol returned in <code>/forex/symbol</code> endpoint for this field.")),
-		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes <code>1, 5, 15, 30, 60, D, W, M </code>.Some timeframes might not be available depending on the exchange.")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("UNIX timestamp. Interval initial value.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("UNIX timestamp. Interval end value.")),
+		mcp.WithDescription("Get candlestick data for forex symbols."),
+		mcp.WithTitleAnnotation("Forex Candles"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(forexCandlesOutputSchema)),
+		mcp.WithString("symbol", mcp.Required(), mcp.Description("Use symbol returned in `/forex/symbol` endpoint for this field.")),
+		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes `1, 5, 15, 30, 60, D, W, M `.Some timeframes might not be available depending on the exchange."), mcp.Enum("1", "5", "15", "30", "60", "D", "W", "M")),
+		mcp.WithNumber("from", schema.Integer(), mcp.Required(), mcp.Description("UNIX timestamp. Interval initial value.")),
+		mcp.WithNumber("to", schema.Integer(), mcp.Required(), mcp.Description("UNIX timestamp. Interval end value.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Forex_candlesHandler(cfg),
+		Handler:    schema.Checked(tool, Forex_candlesHandler(cfg)),
+		Toolset:    "forex",
+		Access:     forexCandlesAccess,
 	}
 }
@@ -1,82 +1,74 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// forexRatesAccess is the plan access get_forex_rates requires, from swagger.json.
+var forexRatesAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// forexratesOutputSchema is the output schema for Forexrates, derived from swagger.json.
+const forexratesOutputSchema = `{
+  "properties": {
+    "base": {
+      "description": "Base currency.",
+      "type": ["string", "null"]
+    },
+    "quote": {
+      "type": ["object", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Forex_ratesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["base"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("base=%v", val))
-		}
-		if val, ok := args["date"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("date=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/forex/rates%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "base", "date")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_forex_rates", Method: "GET", Path: "/forex/rates", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_forex_rates", forexRatesAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateForex_ratesTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_forex_rates",
-		mcp.WithDescription("Forex rates"),
+		mcp.WithDescription("Get rates for all forex pairs. Ideal for currency conversion"),
+		mcp.WithTitleAnnotation("Forex rates"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(forexratesOutputSchema)),
 		mcp.WithString("base", mcp.Description("Base currency. Default to EUR.")),
-		mcp.WithString("date", mcp.Description("Date. Leave blank to get the latest data.")),
+		mcp.WithString("date", mcp.Description("Date. Leave blank to get the latest data."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Forex_ratesHandler(cfg),
+		Handler:    schema.Checked(tool, Forex_ratesHandler(cfg)),
+		Toolset:    "forex",
+		Access:     forexRatesAccess,
 	}
 }
@@ -1,78 +1,86 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// forexSymbolsAccess is the plan access get_forex_symbol requires, from swagger.json.
+var forexSymbolsAccess = plan.Access{Tier: plan.Free}
+
+// forexSymbolListOutputSchema is the output schema for ForexSymbol arrays, derived from swagger.json.
+const forexSymbolListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "description": {
+            "description": "Symbol description",
+            "type": ["string", "null"]
+          },
+          "displaySymbol": {
+            "description": "Display symbol name.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Unique symbol used to identify this symbol used in /forex/candle endpoint.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Forex_symbolsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["exchange"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("exchange=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/forex/symbol%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "exchange")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_forex_symbol", Method: "GET", Path: "/forex/symbol", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_forex_symbol", forexSymbolsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateForex_symbolsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_forex_symbol",
-		mcp.WithDescription("Forex Symbol"),
+		mcp.WithDescription("List supported forex symbols."),
+		mcp.WithTitleAnnotation("Forex Symbol"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(forexSymbolListOutputSchema)),
 		mcp.WithString("exchange", mcp.Required(), mcp.Description("Exchange you want to get the list of symbols from.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Forex_symbolsHandler(cfg),
+		Handler:    schema.Checked(tool, Forex_symbolsHandler(cfg)),
+		Toolset:    "forex",
+		Access:     forexSymbolsAccess,
 	}
 }
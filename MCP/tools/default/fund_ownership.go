@@ -1,82 +1,100 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// fundOwnershipAccess is the plan access get_stock_fund-ownership requires, from swagger.json.
+var fundOwnershipAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// fundOwnershipOutputSchema is the output schema for FundOwnership, derived from swagger.json.
+const fundOwnershipOutputSchema = `{
+  "properties": {
+    "ownership": {
+      "description": "Array of investors with detailed information about their holdings.",
+      "items": {
+        "properties": {
+          "change": {
+            "description": "Number of share changed (net buy or sell) from the last period.",
+            "type": ["integer", "null"]
+          },
+          "filingDate": {
+            "description": "Filing date.",
+            "type": ["string", "null"]
+          },
+          "name": {
+            "description": "Investor's name.",
+            "type": ["string", "null"]
+          },
+          "portfolioPercent": {
+            "description": "Percent of the fund's portfolio comprised of the company's share.",
+            "type": ["number", "null"]
+          },
+          "share": {
+            "description": "Number of shares held by the investor.",
+            "type": ["integer", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol of the company.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Fund_ownershipHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/fund-ownership%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "limit")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_fund-ownership", Method: "GET", Path: "/stock/fund-ownership", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_fund-ownership", fundOwnershipAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateFund_ownershipTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_fund-ownership",
-		mcp.WithDescription("Fund Ownership"),
+		mcp.WithDescription("Get a full list fund and institutional investors of a company in descending order of the number of shares held. Data is sourced from `13F form`, `Schedule 13D` and `13G` for US market, `UK Share Register` for UK market, `SEDI` for Canadian market and equivalent filings for other international markets."),
+		mcp.WithTitleAnnotation("Fund Ownership"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(fundOwnershipOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
-		mcp.WithString("limit", mcp.Description("Limit number of results. Leave empty to get the full list.")),
+		mcp.WithNumber("limit", schema.Integer(), mcp.Description("Limit number of results. Leave empty to get the full list."), mcp.Min(1)),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Fund_ownershipHandler(cfg),
+		Handler:    schema.Checked(tool, Fund_ownershipHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     fundOwnershipAccess,
 	}
 }
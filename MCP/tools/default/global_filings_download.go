@@ -1,78 +1,58 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// globalFilingsDownloadAccess is the plan access get_global-filings_download requires, from swagger.json.
+var globalFilingsDownloadAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
 func Global_filings_downloadHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["documentId"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("documentId=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/global-filings/download%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "documentId")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_global-filings_download", Method: "GET", Path: "/global-filings/download", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return result.Error(ctx, cfg, "get_global-filings_download", globalFilingsDownloadAccess, resp), nil
 		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateGlobal_filings_downloadTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_global-filings_download",
-		mcp.WithDescription("Download Filings"),
+		mcp.WithDescription("Download filings using document ids."),
+		mcp.WithTitleAnnotation("Download Filings"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString("documentId", mcp.Required(), mcp.Description("Document's id. Note that this is different from filingId as 1 filing can contain multiple documents.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Global_filings_downloadHandler(cfg),
+		Handler:    schema.Checked(tool, Global_filings_downloadHandler(cfg)),
+		Toolset:    "global-filings-search",
+		Access:     globalFilingsDownloadAccess,
 	}
 }
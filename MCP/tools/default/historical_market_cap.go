@@ -1,86 +1,93 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// historicalMarketCapAccess is the plan access get_stock_historical-market-cap requires, from swagger.json.
+var historicalMarketCapAccess = plan.Access{Tier: plan.Fundamental2, Note: "Accessible with Fundamental 2 or All in One subscription."}
+
+// historicalMarketCapDataOutputSchema is the output schema for HistoricalMarketCapData, derived from swagger.json.
+const historicalMarketCapDataOutputSchema = `{
+  "properties": {
+    "currency": {
+      "description": "Currency",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of market data.",
+      "items": {
+        "properties": {
+          "atDate": {
+            "description": "Date of the reading",
+            "type": ["string", "null"]
+          },
+          "marketCapitalization": {
+            "description": "Value",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Historical_market_capHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/historical-market-cap%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_historical-market-cap", Method: "GET", Path: "/stock/historical-market-cap", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_historical-market-cap", historicalMarketCapAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateHistorical_market_capTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_historical-market-cap",
-		mcp.WithDescription("Historical Market Cap"),
+		mcp.WithDescription("Get historical market cap data for global companies."),
+		mcp.WithTitleAnnotation("Historical Market Cap"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(historicalMarketCapDataOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Company symbol.")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("From date <code>YYYY-MM-DD</code>.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("To date <code>YYYY-MM-DD</code>.")),
+		mcp.WithString("from", mcp.Required(), mcp.Description("From date `YYYY-MM-DD`."), schema.Format("date")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("To date `YYYY-MM-DD`."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Historical_market_capHandler(cfg),
+		Handler:    schema.Checked(tool, Historical_market_capHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     historicalMarketCapAccess,
 	}
 }
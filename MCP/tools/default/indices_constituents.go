@@ -1,78 +1,110 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// indicesConstituentsAccess is the plan access get_index_constituents requires, from swagger.json.
+var indicesConstituentsAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// indicesConstituentsOutputSchema is the output schema for IndicesConstituents, derived from swagger.json.
+const indicesConstituentsOutputSchema = `{
+  "properties": {
+    "constituents": {
+      "description": "Array of constituents.",
+      "items": {
+        "type": ["string", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "constituentsBreakdown": {
+      "description": "Array of constituents' details.",
+      "items": {
+        "properties": {
+          "cusip": {
+            "description": "Cusip.",
+            "type": ["string", "null"]
+          },
+          "isin": {
+            "description": "ISIN.",
+            "type": ["string", "null"]
+          },
+          "name": {
+            "description": "Name.",
+            "type": ["string", "null"]
+          },
+          "shareClassFIGI": {
+            "description": "Global Share Class FIGI.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Symbol.",
+            "type": ["string", "null"]
+          },
+          "weight": {
+            "description": "Weight.",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Index's symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Indices_constituentsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/index/constituents%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_index_constituents", Method: "GET", Path: "/index/constituents", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_index_constituents", indicesConstituentsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateIndices_constituentsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_index_constituents",
-		mcp.WithDescription("Indices Constituents"),
+		mcp.WithDescription("Get a list of index's constituents. A list of supported indices for this endpoint can be found [here](https://finnhub.io/api/v1/index/list?token=)."),
+		mcp.WithTitleAnnotation("Indices Constituents"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(indicesConstituentsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("symbol")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Indices_constituentsHandler(cfg),
+		Handler:    schema.Checked(tool, Indices_constituentsHandler(cfg)),
+		Toolset:    "etfs-indices",
+		Access:     indicesConstituentsAccess,
 	}
 }
@@ -1,78 +1,91 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// indicesHistoricalConstituentsAccess is the plan access get_index_historical-constituents requires, from swagger.json.
+var indicesHistoricalConstituentsAccess = plan.Access{Tier: plan.Premium, Note: "Premium required."}
+
+// indicesHistoricalConstituentsOutputSchema is the output schema for IndicesHistoricalConstituents, derived from swagger.json.
+const indicesHistoricalConstituentsOutputSchema = `{
+  "properties": {
+    "historicalConstituents": {
+      "description": "Array of historical constituents.",
+      "items": {
+        "properties": {
+          "action": {
+            "description": "add or remove.",
+            "type": ["string", "null"]
+          },
+          "date": {
+            "description": "Date of joining or leaving the index.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Symbol",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Index's symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Indices_historical_constituentsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/index/historical-constituents%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_index_historical-constituents", Method: "GET", Path: "/index/historical-constituents", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_index_historical-constituents", indicesHistoricalConstituentsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateIndices_historical_constituentsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_index_historical-constituents",
-		mcp.WithDescription("Indices Historical Constituents"),
+		mcp.WithDescription("Get full history of index's constituents including symbols and dates of joining and leaving the Index. A list of supported indices for this endpoint can be found [here](https://finnhub.io/api/v1/index/historical-list?token=)."),
+		mcp.WithTitleAnnotation("Indices Historical Constituents"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(indicesHistoricalConstituentsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("symbol")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Indices_historical_constituentsHandler(cfg),
+		Handler:    schema.Checked(tool, Indices_historical_constituentsHandler(cfg)),
+		Toolset:    "etfs-indices",
+		Access:     indicesHistoricalConstituentsAccess,
 	}
 }
@@ -1,86 +1,101 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// insiderSentimentAccess is the plan access get_stock_insider-sentiment requires, from swagger.json.
+var insiderSentimentAccess = plan.Access{Tier: plan.Free}
+
+// insiderSentimentsOutputSchema is the output schema for InsiderSentiments, derived from swagger.json.
+const insiderSentimentsOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Array of sentiment data.",
+      "items": {
+        "properties": {
+          "change": {
+            "description": "Net buying/selling from all insiders' transactions.",
+            "type": ["integer", "null"]
+          },
+          "month": {
+            "description": "Month.",
+            "type": ["integer", "null"]
+          },
+          "mspr": {
+            "description": "Monthly share purchase ratio.",
+            "type": ["number", "null"]
+          },
+          "symbol": {
+            "description": "Symbol.",
+            "type": ["string", "null"]
+          },
+          "year": {
+            "description": "Year.",
+            "type": ["integer", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol of the company.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Insider_sentimentHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/insider-sentiment%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_insider-sentiment", Method: "GET", Path: "/stock/insider-sentiment", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_insider-sentiment", insiderSentimentAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateInsider_sentimentTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_insider-sentiment",
-		mcp.WithDescription("Insider Sentiment"),
+		mcp.WithDescription("Get insider sentiment data for US companies calculated using method discussed [here](https://medium.com/@stock-api/finnhub-insiders-sentiment-analysis-cc43f9f64b3a). The MSPR ranges from -100 for the most negative to 100 for the most positive which can signal price changes in the coming 30-90 days."),
+		mcp.WithTitleAnnotation("Insider Sentiment"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(insiderSentimentsOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("From date: 2020-03-15.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("To date: 2020-03-16.")),
+		mcp.WithString("from", mcp.Required(), mcp.Description("From date: 2020-03-15."), schema.Format("date")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("To date: 2020-03-16."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Insider_sentimentHandler(cfg),
+		Handler:    schema.Checked(tool, Insider_sentimentHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     insiderSentimentAccess,
 	}
 }
@@ -1,78 +1,103 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// institutionalProfileAccess is the plan access get_institutional_profile requires, from swagger.json.
+var institutionalProfileAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// institutionalProfileOutputSchema is the output schema for InstitutionalProfile, derived from swagger.json.
+const institutionalProfileOutputSchema = `{
+  "properties": {
+    "cik": {
+      "description": "CIK.",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of investors.",
+      "items": {
+        "properties": {
+          "cik": {
+            "description": "Investor's company CIK.",
+            "type": ["string", "null"]
+          },
+          "firmType": {
+            "description": "Firm type.",
+            "type": ["string", "null"]
+          },
+          "manager": {
+            "description": "Manager.",
+            "type": ["string", "null"]
+          },
+          "philosophy": {
+            "description": "Investing philosophy.",
+            "type": ["string", "null"]
+          },
+          "profile": {
+            "description": "Profile info.",
+            "type": ["string", "null"]
+          },
+          "profileImg": {
+            "description": "Profile image.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Institutional_profileHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["cik"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("cik=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/institutional/profile%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "cik")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_institutional_profile", Method: "GET", Path: "/institutional/profile", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_institutional_profile", institutionalProfileAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateInstitutional_profileTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_institutional_profile",
-		mcp.WithDescription("Institutional Profile"),
+		mcp.WithDescription("Get a list of well-known institutional investors. Currently support 60+ profiles."),
+		mcp.WithTitleAnnotation("Institutional Profile"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(institutionalProfileOutputSchema)),
 		mcp.WithString("cik", mcp.Description("Filter by CIK. Leave blank to get the full list.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Institutional_profileHandler(cfg),
+		Handler:    schema.Checked(tool, Institutional_profileHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     institutionalProfileAccess,
 	}
 }
@@ -1,90 +1,113 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// internationalFilingsAccess is the plan access get_stock_international-filings requires, from swagger.json.
+var internationalFilingsAccess = plan.Access{Tier: plan.Enterprise, Note: "Access approved on a case by case basis"}
+
+// internationalFilingListOutputSchema is the output schema for InternationalFiling arrays, derived from swagger.json.
+const internationalFilingListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "category": {
+            "description": "Category.",
+            "type": ["string", "null"]
+          },
+          "companyName": {
+            "description": "Company name.",
+            "type": ["string", "null"]
+          },
+          "country": {
+            "description": "Country.",
+            "type": ["string", "null"]
+          },
+          "description": {
+            "description": "Document's description.",
+            "type": ["string", "null"]
+          },
+          "filedDate": {
+            "description": "Filed date %Y-%m-%d %H:%M:%S.",
+            "type": ["string", "null"]
+          },
+          "language": {
+            "description": "Language.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Symbol.",
+            "type": ["string", "null"]
+          },
+          "title": {
+            "description": "Document's title.",
+            "type": ["string", "null"]
+          },
+          "url": {
+            "description": "Url.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func International_filingsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["country"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("country=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/international-filings%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "country", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_international-filings", Method: "GET", Path: "/stock/international-filings", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_international-filings", internationalFilingsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateInternational_filingsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_international-filings",
-		mcp.WithDescription("International Filings"),
+		mcp.WithDescription("List filings for international companies. Limit to 500 documents at a time. These are the documents we use to source our fundamental data. Enterprise clients who need access to the full filings for global markets should contact us for the access."),
+		mcp.WithTitleAnnotation("International Filings"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(internationalFilingListOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("Symbol. Leave empty to list latest filings.")),
 		mcp.WithString("country", mcp.Description("Filter by country using country's 2-letter code.")),
-		mcp.WithString("from", mcp.Description("From date: 2023-01-15.")),
-		mcp.WithString("to", mcp.Description("To date: 2023-12-16.")),
+		mcp.WithString("from", mcp.Description("From date: 2023-01-15."), schema.Format("date")),
+		mcp.WithString("to", mcp.Description("To date: 2023-12-16."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    International_filingsHandler(cfg),
+		Handler:    schema.Checked(tool, International_filingsHandler(cfg)),
+		Toolset:    "global-filings-search",
+		Access:     internationalFilingsAccess,
 	}
 }
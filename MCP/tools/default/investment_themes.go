@@ -1,78 +1,83 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// investmentThemesAccess is the plan access get_stock_investment-theme requires, from swagger.json.
+var investmentThemesAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// investmentThemesOutputSchema is the output schema for InvestmentThemes, derived from swagger.json.
+const investmentThemesOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Investment theme portfolio.",
+      "items": {
+        "properties": {
+          "symbol": {
+            "description": "Symbol",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "theme": {
+      "description": "Investment theme",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Investment_themesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["theme"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("theme=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/investment-theme%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "theme")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_investment-theme", Method: "GET", Path: "/stock/investment-theme", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_investment-theme", investmentThemesAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateInvestment_themesTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_investment-theme",
-		mcp.WithDescription("Investment Themes (Thematic Investing)"),
-		mcp.WithString("theme", mcp.Required(), mcp.Description("Investment theme. A full list of themes supported can be found <a target=\"_blank\" href=\"https://docs.google.com/spreadsheets/d/1ULj9xDh4iPoQj279M084adZ2_S852ttRthKKJ7madYc/edit?usp=sharing\">here</a>.")),
+		mcp.WithDescription("Thematic investing involves creating a portfolio (or portion of a portfolio) by gathering together a collection of companies involved in certain areas that you predict will generate above-market returns over the long term. Themes can be based on a concept such as ageing populations or a sub-sector such as robotics, and drones. Thematic investing focuses on predicted long-term trends rather than specific companies or sectors, enabling investors to access structural, one-off shifts that can change an entire industry.\n\nThis endpoint will help you get portfolios of different investment themes that are changing our life and are the way of the future.\n\nA full list of themes supported can be found [here](https://docs.google.com/spreadsheets/d/1ULj9xDh4iPoQj279M084adZ2_S852ttRthKKJ7madYc/edit?usp=sharing). The theme coverage and portfolios are updated bi-weekly by our analysts. Our approach excludes penny, super-small cap and illiquid stocks."),
+		mcp.WithTitleAnnotation("Investment Themes (Thematic Investing)"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(investmentThemesOutputSchema)),
+		mcp.WithString("theme", mcp.Required(), mcp.Description("Investment theme. A full list of themes supported can be found [here](https://docs.google.com/spreadsheets/d/1ULj9xDh4iPoQj279M084adZ2_S852ttRthKKJ7madYc/edit?usp=sharing).")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Investment_themesHandler(cfg),
+		Handler:    schema.Checked(tool, Investment_themesHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     investmentThemesAccess,
 	}
 }
@@ -1,78 +1,95 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// marketHolidayAccess is the plan access get_stock_market-holiday requires, from swagger.json.
+var marketHolidayAccess = plan.Access{Tier: plan.Free}
+
+// marketHolidayOutputSchema is the output schema for MarketHoliday, derived from swagger.json.
+const marketHolidayOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Array of holidays.",
+      "items": {
+        "properties": {
+          "atDate": {
+            "description": "Date.",
+            "type": ["string", "null"]
+          },
+          "eventName": {
+            "description": "Holiday's name.",
+            "type": ["string", "null"]
+          },
+          "tradingHour": {
+            "description": "Trading hours for this day if the market is partially closed only.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "exchange": {
+      "description": "Exchange.",
+      "type": ["string", "null"]
+    },
+    "timezone": {
+      "description": "Timezone.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Market_holidayHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["exchange"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("exchange=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/market-holiday%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "exchange")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_market-holiday", Method: "GET", Path: "/stock/market-holiday", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_market-holiday", marketHolidayAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateMarket_holidayTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_market-holiday",
-		mcp.WithDescription("Market Holiday"),
+		mcp.WithDescription("Get a list of holidays for global exchanges."),
+		mcp.WithTitleAnnotation("Market Holiday"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(marketHolidayOutputSchema)),
 		mcp.WithString("exchange", mcp.Required(), mcp.Description("Exchange code.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Market_holidayHandler(cfg),
+		Handler:    schema.Checked(tool, Market_holidayHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     marketHolidayAccess,
 	}
 }
@@ -1,78 +1,90 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// marketStatusAccess is the plan access get_stock_market-status requires, from swagger.json.
+var marketStatusAccess = plan.Access{Tier: plan.Free}
+
+// marketStatusOutputSchema is the output schema for MarketStatus, derived from swagger.json.
+const marketStatusOutputSchema = `{
+  "properties": {
+    "exchange": {
+      "description": "Exchange.",
+      "type": ["string", "null"]
+    },
+    "holiday": {
+      "description": "Holiday event.",
+      "type": ["string", "null"]
+    },
+    "isOpen": {
+      "description": "Whether the market is open at the moment.",
+      "type": ["boolean", "null"]
+    },
+    "session": {
+      "description": "Market session. Can be 1 of the following values: pre-market, regular, post-market or null if the market is closed.",
+      "type": ["string", "null"]
+    },
+    "t": {
+      "description": "Current timestamp.",
+      "type": ["integer", "null"]
+    },
+    "timezone": {
+      "description": "Timezone.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Market_statusHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["exchange"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("exchange=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/market-status%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "exchange")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_market-status", Method: "GET", Path: "/stock/market-status", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_market-status", marketStatusAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateMarket_statusTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_market-status",
-		mcp.WithDescription("Market Status"),
+		mcp.WithDescription("Get current market status for global exchanges (whether exchanges are open or close)."),
+		mcp.WithTitleAnnotation("Market Status"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(marketStatusOutputSchema)),
 		mcp.WithString("exchange", mcp.Required(), mcp.Description("Exchange code.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Market_statusHandler(cfg),
+		Handler:    schema.Checked(tool, Market_statusHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     marketStatusAccess,
 	}
 }
@@ -1,78 +1,73 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// mutualFundEetAccess is the plan access get_mutual-fund_eet requires, from swagger.json.
+var mutualFundEetAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// mutualFundEetOutputSchema is the output schema for MutualFundEet, derived from swagger.json.
+const mutualFundEetOutputSchema = `{
+  "properties": {
+    "data": {
+      "type": ["object", "null"]
+    },
+    "isin": {
+      "description": "ISIN.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Mutual_fund_eetHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["isin"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("isin=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/mutual-fund/eet%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "isin")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_mutual-fund_eet", Method: "GET", Path: "/mutual-fund/eet", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_mutual-fund_eet", mutualFundEetAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateMutual_fund_eetTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_mutual-fund_eet",
-		mcp.WithDescription("Mutual Funds EET"),
+		mcp.WithDescription("Get EET data for EU funds. For PAIs data, please see the EET PAI endpoint."),
+		mcp.WithTitleAnnotation("Mutual Funds EET"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(mutualFundEetOutputSchema)),
 		mcp.WithString("isin", mcp.Required(), mcp.Description("ISIN.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Mutual_fund_eetHandler(cfg),
+		Handler:    schema.Checked(tool, Mutual_fund_eetHandler(cfg)),
+		Toolset:    "mutual-funds",
+		Access:     mutualFundEetAccess,
 	}
 }
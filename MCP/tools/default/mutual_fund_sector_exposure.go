@@ -1,82 +1,88 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// mutualFundSectorExposureAccess is the plan access get_mutual-fund_sector requires, from swagger.json.
+var mutualFundSectorExposureAccess = plan.Access{Tier: plan.Premium, Note: "Premium required."}
+
+// mutualFundSectorExposureOutputSchema is the output schema for MutualFundSectorExposure, derived from swagger.json.
+const mutualFundSectorExposureOutputSchema = `{
+  "properties": {
+    "sectorExposure": {
+      "description": "Array of sector and exposure levels.",
+      "items": {
+        "properties": {
+          "exposure": {
+            "description": "Percent of exposure.",
+            "type": ["number", "null"]
+          },
+          "sector": {
+            "description": "Sector",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Mutual symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Mutual_fund_sector_exposureHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["isin"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("isin=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/mutual-fund/sector%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "isin")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_mutual-fund_sector", Method: "GET", Path: "/mutual-fund/sector", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_mutual-fund_sector", mutualFundSectorExposureAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateMutual_fund_sector_exposureTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_mutual-fund_sector",
-		mcp.WithDescription("Mutual Funds Sector Exposure"),
+		mcp.WithDescription("Get Mutual Funds sector exposure data."),
+		mcp.WithTitleAnnotation("Mutual Funds Sector Exposure"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(mutualFundSectorExposureOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("Mutual Fund symbol.")),
 		mcp.WithString("isin", mcp.Description("Fund's isin.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Mutual_fund_sector_exposureHandler(cfg),
+		Handler:    schema.Checked(tool, Mutual_fund_sector_exposureHandler(cfg)),
+		Toolset:    "mutual-funds",
+		Access:     mutualFundSectorExposureAccess,
 	}
 }
@@ -1,78 +1,107 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// newsSentimentAccess is the plan access get_news-sentiment requires, from swagger.json.
+var newsSentimentAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// newsSentimentOutputSchema is the output schema for NewsSentiment, derived from swagger.json.
+const newsSentimentOutputSchema = `{
+  "properties": {
+    "buzz": {
+      "properties": {
+        "articlesInLastWeek": {
+          "type": ["integer", "null"]
+        },
+        "buzz": {
+          "type": ["number", "null"]
+        },
+        "weeklyAverage": {
+          "type": ["number", "null"]
+        }
+      },
+      "type": ["object", "null"]
+    },
+    "companyNewsScore": {
+      "description": "News score.",
+      "type": ["number", "null"]
+    },
+    "sectorAverageBullishPercent": {
+      "description": "Sector average bullish percent.",
+      "type": ["number", "null"]
+    },
+    "sectorAverageNewsScore": {
+      "description": "Sectore average score.",
+      "type": ["number", "null"]
+    },
+    "sentiment": {
+      "properties": {
+        "bearishPercent": {
+          "type": ["number", "null"]
+        },
+        "bullishPercent": {
+          "type": ["number", "null"]
+        }
+      },
+      "type": ["object", "null"]
+    },
+    "symbol": {
+      "description": "Requested symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func News_sentimentHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/news-sentiment%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_news-sentiment", Method: "GET", Path: "/news-sentiment", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_news-sentiment", newsSentimentAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateNews_sentimentTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_news-sentiment",
-		mcp.WithDescription("News Sentiment"),
+		mcp.WithDescription("Get company's news sentiment and statistics. This endpoint is only available for US companies."),
+		mcp.WithTitleAnnotation("News Sentiment"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(newsSentimentOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Company symbol.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    News_sentimentHandler(cfg),
+		Handler:    schema.Checked(tool, News_sentimentHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     newsSentimentAccess,
 	}
 }
@@ -1,86 +1,101 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// pressReleasesAccess is the plan access get_press-releases requires, from swagger.json.
+var pressReleasesAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// pressReleaseOutputSchema is the output schema for PressRelease, derived from swagger.json.
+const pressReleaseOutputSchema = `{
+  "properties": {
+    "majorDevelopment": {
+      "description": "Array of major developments.",
+      "items": {
+        "properties": {
+          "datetime": {
+            "description": "Published time in YYYY-MM-DD HH:MM:SS format.",
+            "type": ["string", "null"]
+          },
+          "description": {
+            "description": "Development description.",
+            "type": ["string", "null"]
+          },
+          "headline": {
+            "description": "Development headline.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Company symbol.",
+            "type": ["string", "null"]
+          },
+          "url": {
+            "description": "URL.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Company symbol.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Press_releasesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/press-releases%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_press-releases", Method: "GET", Path: "/press-releases", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_press-releases", pressReleasesAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreatePress_releasesTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_press-releases",
-		mcp.WithDescription("Major Press Releases"),
+		mcp.WithDescription("Get latest major press releases of a company. This data can be used to highlight the most significant events comprised of mostly press releases sourced from the exchanges, BusinessWire, AccessWire, GlobeNewswire, Newsfile, and PRNewswire.\n\nFull-text press releases data is available for Enterprise clients. [Contact Us](mailto:support@finnhub.io) to learn more."),
+		mcp.WithTitleAnnotation("Major Press Releases"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(pressReleaseOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Company symbol.")),
-		mcp.WithString("from", mcp.Description("From time: 2020-01-01.")),
-		mcp.WithString("to", mcp.Description("To time: 2020-01-05.")),
+		mcp.WithString("from", mcp.Description("From time: 2020-01-01."), schema.Format("date")),
+		mcp.WithString("to", mcp.Description("To time: 2020-01-05."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Press_releasesHandler(cfg),
+		Handler:    schema.Checked(tool, Press_releasesHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     pressReleasesAccess,
 	}
 }
@@ -1,78 +1,94 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// priceTargetAccess is the plan access get_stock_price-target requires, from swagger.json.
+var priceTargetAccess = plan.Access{Tier: plan.Premium, Note: "Premium required."}
+
+// priceTargetOutputSchema is the output schema for PriceTarget, derived from swagger.json.
+const priceTargetOutputSchema = `{
+  "properties": {
+    "lastUpdated": {
+      "description": "Updated time of the data",
+      "type": ["string", "null"]
+    },
+    "numberAnalysts": {
+      "description": "Number of Analysts.",
+      "type": ["integer", "null"]
+    },
+    "symbol": {
+      "description": "Company symbol.",
+      "type": ["string", "null"]
+    },
+    "targetHigh": {
+      "description": "Highes analysts' target.",
+      "type": ["number", "null"]
+    },
+    "targetLow": {
+      "description": "Lowest analysts' target.",
+      "type": ["number", "null"]
+    },
+    "targetMean": {
+      "description": "Mean of all analysts' targets.",
+      "type": ["number", "null"]
+    },
+    "targetMedian": {
+      "description": "Median of all analysts' targets.",
+      "type": ["number", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Price_targetHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/price-target%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_price-target", Method: "GET", Path: "/stock/price-target", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_price-target", priceTargetAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreatePrice_targetTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_price-target",
-		mcp.WithDescription("Price Target"),
+		mcp.WithDescription("Get latest price target consensus."),
+		mcp.WithTitleAnnotation("Price Target"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(priceTargetOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol of the company: AAPL.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Price_targetHandler(cfg),
+		Handler:    schema.Checked(tool, Price_targetHandler(cfg)),
+		Toolset:    "stock-estimates",
+		Access:     priceTargetAccess,
 	}
 }
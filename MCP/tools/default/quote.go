@@ -1,78 +1,94 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// quoteAccess is the plan access get_quote requires, from swagger.json.
+var quoteAccess = plan.Access{Tier: plan.Free}
+
+// quoteOutputSchema is the output schema for Quote, derived from swagger.json.
+const quoteOutputSchema = `{
+  "properties": {
+    "c": {
+      "description": "Current price",
+      "type": ["number", "null"]
+    },
+    "d": {
+      "description": "Change",
+      "type": ["number", "null"]
+    },
+    "dp": {
+      "description": "Percent change",
+      "type": ["number", "null"]
+    },
+    "h": {
+      "description": "High price of the day",
+      "type": ["number", "null"]
+    },
+    "l": {
+      "description": "Low price of the day",
+      "type": ["number", "null"]
+    },
+    "o": {
+      "description": "Open price of the day",
+      "type": ["number", "null"]
+    },
+    "pc": {
+      "description": "Previous close price",
+      "type": ["number", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func QuoteHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/quote%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_quote", Method: "GET", Path: "/quote", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_quote", quoteAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateQuoteTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_quote",
-		mcp.WithDescription("Quote"),
+		mcp.WithDescription("Get real-time quote data for US stocks. Constant polling is not recommended. Use websocket if you need real-time updates.\n\nReal-time stock prices for international markets are supported for Enterprise clients via our partner's feed. [Contact Us](mailto:support@finnhub.io) to learn more."),
+		mcp.WithTitleAnnotation("Quote"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(quoteOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    QuoteHandler(cfg),
+		Handler:    schema.Checked(tool, QuoteHandler(cfg)),
+		Toolset:    "stock-price",
+		Access:     quoteAccess,
 	}
 }
@@ -1,82 +1,91 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// revenueBreakdownAccess is the plan access get_stock_revenue-breakdown requires, from swagger.json.
+var revenueBreakdownAccess = plan.Access{Tier: plan.Premium, Note: "Premium"}
+
+// revenueBreakdownOutputSchema is the output schema for RevenueBreakdown, derived from swagger.json.
+const revenueBreakdownOutputSchema = `{
+  "properties": {
+    "cik": {
+      "description": "CIK",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Array of revenue breakdown over multiple periods.",
+      "items": {
+        "properties": {
+          "accessNumber": {
+            "description": "Access number of the report from which the data is sourced.",
+            "type": ["string", "null"]
+          },
+          "breakdown": {
+            "type": ["object", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "symbol": {
+      "description": "Symbol",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Revenue_breakdownHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["cik"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("cik=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/revenue-breakdown%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "cik")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_revenue-breakdown", Method: "GET", Path: "/stock/revenue-breakdown", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_revenue-breakdown", revenueBreakdownAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateRevenue_breakdownTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_revenue-breakdown",
-		mcp.WithDescription("Revenue Breakdown"),
+		mcp.WithDescription("Get revenue breakdown as-reporetd by product and geography. Users on personal plans can access data for US companies which disclose their revenue breakdown in the annual or quarterly reports.\n\nGlobal standardized revenue breakdown/segments data is available for Enterprise users. [Contact us](mailto:support@finnhub.io) to inquire about the access for Global standardized data."),
+		mcp.WithTitleAnnotation("Revenue Breakdown"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(revenueBreakdownOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("Symbol.")),
 		mcp.WithString("cik", mcp.Description("CIK.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Revenue_breakdownHandler(cfg),
+		Handler:    schema.Checked(tool, Revenue_breakdownHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     revenueBreakdownAccess,
 	}
 }
@@ -1,78 +1,78 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// revenueBreakdown2Access is the plan access get_stock_revenue-breakdown2 requires, from swagger.json.
+var revenueBreakdown2Access = plan.Access{Tier: plan.Premium, Note: "Premium"}
+
+// revenueBreakdown2OutputSchema is the output schema for RevenueBreakdown2, derived from swagger.json.
+const revenueBreakdown2OutputSchema = `{
+  "properties": {
+    "currency": {
+      "description": "currency",
+      "type": ["string", "null"]
+    },
+    "data": {
+      "description": "Revenue breakdown data.",
+      "type": ["object", "null"]
+    },
+    "symbol": {
+      "description": "Symbol",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Revenue_breakdown2Handler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/revenue-breakdown2%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_revenue-breakdown2", Method: "GET", Path: "/stock/revenue-breakdown2", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_revenue-breakdown2", revenueBreakdown2Access, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateRevenue_breakdown2Tool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_revenue-breakdown2",
-		mcp.WithDescription("Revenue Breakdown & KPI"),
+		mcp.WithDescription("Get standardized revenue breakdown and KPIs data for 30,000+ global companies."),
+		mcp.WithTitleAnnotation("Revenue Breakdown & KPI"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(revenueBreakdown2OutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Revenue_breakdown2Handler(cfg),
+		Handler:    schema.Checked(tool, Revenue_breakdown2Handler(cfg)),
+		Toolset:    "enterprise-data",
+		Access:     revenueBreakdown2Access,
 	}
 }
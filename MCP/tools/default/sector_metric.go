@@ -1,78 +1,87 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// sectorMetricAccess is the plan access get_sector_metrics requires, from swagger.json.
+var sectorMetricAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// sectorMetricOutputSchema is the output schema for SectorMetric, derived from swagger.json.
+const sectorMetricOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Metrics for each sector.",
+      "items": {
+        "properties": {
+          "metrics": {
+            "description": "Metrics data in key-value format. a and m fields are for average and median respectively.",
+            "type": ["object", "null"]
+          },
+          "sector": {
+            "description": "Sector",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "region": {
+      "description": "Region.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Sector_metricHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["region"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("region=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/sector/metrics%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "region")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_sector_metrics", Method: "GET", Path: "/sector/metrics", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_sector_metrics", sectorMetricAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateSector_metricTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_sector_metrics",
-		mcp.WithDescription("Sector Metrics"),
-		mcp.WithString("region", mcp.Required(), mcp.Description("Region. A list of supported values for this field can be found <a href=\"https://docs.google.com/spreadsheets/d/1afedyv7yWJ-z7pMjaAZK-f6ENY3mI3EBCk95QffpoHw/edit?usp=sharing\" target=\"_blank\">here</a>.")),
+		mcp.WithDescription("Get ratios for different sectors and regions/indices."),
+		mcp.WithTitleAnnotation("Sector Metrics"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(sectorMetricOutputSchema)),
+		mcp.WithString("region", mcp.Required(), mcp.Description("Region. A list of supported values for this field can be found [here](https://docs.google.com/spreadsheets/d/1afedyv7yWJ-z7pMjaAZK-f6ENY3mI3EBCk95QffpoHw/edit?usp=sharing).")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Sector_metricHandler(cfg),
+		Handler:    schema.Checked(tool, Sector_metricHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     sectorMetricAccess,
 	}
 }
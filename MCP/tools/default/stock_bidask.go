@@ -1,78 +1,86 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// stockBidaskAccess is the plan access get_stock_bidask requires, from swagger.json.
+var stockBidaskAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// lastBidAskOutputSchema is the output schema for LastBid-Ask, derived from swagger.json.
+const lastBidAskOutputSchema = `{
+  "properties": {
+    "a": {
+      "description": "Ask price.",
+      "type": ["number", "null"]
+    },
+    "av": {
+      "description": "Ask volume.",
+      "type": ["number", "null"]
+    },
+    "b": {
+      "description": "Bid price.",
+      "type": ["number", "null"]
+    },
+    "bv": {
+      "description": "Bid volume.",
+      "type": ["number", "null"]
+    },
+    "t": {
+      "description": "Reference UNIX timestamp in ms.",
+      "type": ["integer", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Stock_bidaskHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/bidask%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_bidask", Method: "GET", Path: "/stock/bidask", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_bidask", stockBidaskAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateStock_bidaskTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_bidask",
-		mcp.WithDescription("Last Bid-Ask"),
+		mcp.WithDescription("Get last bid/ask data for US stocks."),
+		mcp.WithTitleAnnotation("Last Bid-Ask"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(lastBidAskOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Stock_bidaskHandler(cfg),
+		Handler:    schema.Checked(tool, Stock_bidaskHandler(cfg)),
+		Toolset:    "stock-price",
+		Access:     stockBidaskAccess,
 	}
 }
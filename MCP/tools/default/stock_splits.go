@@ -1,86 +1,92 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// stockSplitsAccess is the plan access get_stock_split requires, from swagger.json.
+var stockSplitsAccess = plan.Access{Tier: plan.Premium, Note: "Premium required."}
+
+// splitListOutputSchema is the output schema for Split arrays, derived from swagger.json.
+const splitListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "date": {
+            "description": "Split date.",
+            "type": ["string", "null"]
+          },
+          "fromFactor": {
+            "description": "From factor.",
+            "type": ["number", "null"]
+          },
+          "symbol": {
+            "description": "Symbol.",
+            "type": ["string", "null"]
+          },
+          "toFactor": {
+            "description": "To factor.",
+            "type": ["number", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Stock_splitsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/split%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_split", Method: "GET", Path: "/stock/split", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_split", stockSplitsAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateStock_splitsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_split",
-		mcp.WithDescription("Splits"),
+		mcp.WithDescription("Get splits data for stocks."),
+		mcp.WithTitleAnnotation("Splits"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(splitListOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol.")),
-		mcp.WithString("from", mcp.Required(), mcp.Description("YYYY-MM-DD.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("YYYY-MM-DD.")),
+		mcp.WithString("from", mcp.Required(), mcp.Description("YYYY-MM-DD."), schema.Format("date")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("YYYY-MM-DD."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Stock_splitsHandler(cfg),
+		Handler:    schema.Checked(tool, Stock_splitsHandler(cfg)),
+		Toolset:    "stock-price",
+		Access:     stockSplitsAccess,
 	}
 }
@@ -1,82 +1,74 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// supportResistanceAccess is the plan access get_scan_support-resistance requires, from swagger.json.
+var supportResistanceAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// supportResistanceOutputSchema is the output schema for SupportResistance, derived from swagger.json.
+const supportResistanceOutputSchema = `{
+  "properties": {
+    "levels": {
+      "description": "Array of support and resistance levels.",
+      "items": {
+        "type": ["number", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Support_resistanceHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["resolution"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("resolution=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/scan/support-resistance%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "resolution")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_scan_support-resistance", Method: "GET", Path: "/scan/support-resistance", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_scan_support-resistance", supportResistanceAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateSupport_resistanceTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_scan_support-resistance",
-		mcp.WithDescription("Support/Resistance"),
+		mcp.WithDescription("Get support and resistance levels for a symbol."),
+		mcp.WithTitleAnnotation("Support/Resistance"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(supportResistanceOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Symbol")),
-		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes <code>1, 5, 15, 30, 60, D, W, M </code>.Some timeframes might not be available depending on the exchange.")),
+		mcp.WithString("resolution", mcp.Required(), mcp.Description("Supported resolution includes `1, 5, 15, 30, 60, D, W, M `.Some timeframes might not be available depending on the exchange."), mcp.Enum("1", "5", "15", "30", "60", "D", "W", "M")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Support_resistanceHandler(cfg),
+		Handler:    schema.Checked(tool, Support_resistanceHandler(cfg)),
+		Toolset:    "technical-analysis",
+		Access:     supportResistanceAccess,
 	}
 }
@@ -1,82 +1,96 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// symbolChangeAccess is the plan access get_ca_symbol-change requires, from swagger.json.
+var symbolChangeAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// symbolChangeOutputSchema is the output schema for SymbolChange, derived from swagger.json.
+const symbolChangeOutputSchema = `{
+  "properties": {
+    "data": {
+      "description": "Array of symbol change events.",
+      "items": {
+        "properties": {
+          "atDate": {
+            "description": "Event's date.",
+            "type": ["string", "null"]
+          },
+          "newSymbol": {
+            "description": "New symbol.",
+            "type": ["string", "null"]
+          },
+          "oldSymbol": {
+            "description": "Old symbol.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    },
+    "fromDate": {
+      "description": "From date.",
+      "type": ["string", "null"]
+    },
+    "toDate": {
+      "description": "To date.",
+      "type": ["string", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Symbol_changeHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/ca/symbol-change%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_ca_symbol-change", Method: "GET", Path: "/ca/symbol-change", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_ca_symbol-change", symbolChangeAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateSymbol_changeTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_ca_symbol-change",
-		mcp.WithDescription("Symbol Change"),
-		mcp.WithString("from", mcp.Required(), mcp.Description("From date <code>YYYY-MM-DD</code>.")),
-		mcp.WithString("to", mcp.Required(), mcp.Description("To date <code>YYYY-MM-DD</code>.")),
+		mcp.WithDescription("Get a list of symbol changes for US-listed, EU-listed, NSE and ASX securities. Limit to 2000 events at a time."),
+		mcp.WithTitleAnnotation("Symbol Change"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(symbolChangeOutputSchema)),
+		mcp.WithString("from", mcp.Required(), mcp.Description("From date `YYYY-MM-DD`."), schema.Format("date")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("To date `YYYY-MM-DD`."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Symbol_changeHandler(cfg),
+		Handler:    schema.Checked(tool, Symbol_changeHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     symbolChangeAccess,
 	}
 }
@@ -1,82 +1,96 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// symbolSearchAccess is the plan access get_search requires, from swagger.json.
+var symbolSearchAccess = plan.Access{Tier: plan.Free}
+
+// symbolLookupOutputSchema is the output schema for SymbolLookup, derived from swagger.json.
+const symbolLookupOutputSchema = `{
+  "properties": {
+    "count": {
+      "description": "Number of results.",
+      "type": ["integer", "null"]
+    },
+    "result": {
+      "description": "Array of search results.",
+      "items": {
+        "properties": {
+          "description": {
+            "description": "Symbol description",
+            "type": ["string", "null"]
+          },
+          "displaySymbol": {
+            "description": "Display symbol name.",
+            "type": ["string", "null"]
+          },
+          "symbol": {
+            "description": "Unique symbol used to identify this symbol used in /stock/candle endpoint.",
+            "type": ["string", "null"]
+          },
+          "type": {
+            "description": "Security type.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Symbol_searchHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["q"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("q=%v", val))
-		}
-		if val, ok := args["exchange"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("exchange=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/search%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "q", "exchange")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_search", Method: "GET", Path: "/search", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_search", symbolSearchAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateSymbol_searchTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_search",
-		mcp.WithDescription("Symbol Lookup"),
+		mcp.WithDescription("Search for best-matching symbols based on your query. You can input anything from symbol, security's name to ISIN and Cusip."),
+		mcp.WithTitleAnnotation("Symbol Lookup"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(symbolLookupOutputSchema)),
 		mcp.WithString("q", mcp.Required(), mcp.Description("Query text can be symbol, name, isin, or cusip.")),
 		mcp.WithString("exchange", mcp.Description("Exchange limit.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Symbol_searchHandler(cfg),
+		Handler:    schema.Checked(tool, Symbol_searchHandler(cfg)),
+		Toolset:    "stock-fundamentals",
+		Access:     symbolSearchAccess,
 	}
 }
@@ -1,78 +1,99 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// transcriptsListAccess is the plan access get_stock_transcripts_list requires, from swagger.json.
+var transcriptsListAccess = plan.Access{Tier: plan.Premium, Note: "Premium required."}
+
+// earningsCallTranscriptsListOutputSchema is the output schema for EarningsCallTranscriptsList, derived from swagger.json.
+const earningsCallTranscriptsListOutputSchema = `{
+  "properties": {
+    "symbol": {
+      "description": "Company symbol.",
+      "type": ["string", "null"]
+    },
+    "transcripts": {
+      "description": "Array of transcripts' metadata",
+      "items": {
+        "properties": {
+          "id": {
+            "description": "Transcript's ID used to get the full transcript.",
+            "type": ["string", "null"]
+          },
+          "quarter": {
+            "description": "Quarter of earnings result in the case of earnings call transcript.",
+            "type": ["integer", "null"]
+          },
+          "time": {
+            "description": "Time of the event.",
+            "type": ["string", "null"]
+          },
+          "title": {
+            "description": "Title.",
+            "type": ["string", "null"]
+          },
+          "year": {
+            "description": "Year of earnings result in the case of earnings call transcript.",
+            "type": ["integer", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Transcripts_listHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/transcripts/list%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_transcripts_list", Method: "GET", Path: "/stock/transcripts/list", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_transcripts_list", transcriptsListAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateTranscripts_listTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_transcripts_list",
-		mcp.WithDescription("Earnings Call Transcripts List"),
+		mcp.WithDescription("List earnings call transcripts' metadata. This endpoint is available for US, UK, European, Australian and Canadian companies."),
+		mcp.WithTitleAnnotation("Earnings Call Transcripts List"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(earningsCallTranscriptsListOutputSchema)),
 		mcp.WithString("symbol", mcp.Required(), mcp.Description("Company symbol: AAPL. Leave empty to list the latest transcripts")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Transcripts_listHandler(cfg),
+		Handler:    schema.Checked(tool, Transcripts_listHandler(cfg)),
+		Toolset:    "alternative-data",
+		Access:     transcriptsListAccess,
 	}
 }
@@ -1,86 +1,100 @@
+// Code generated by gentools from swagger.json. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/params"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/internal/result"
+	"github.com/finnhub-api/mcp-server/internal/schema"
+	"github.com/finnhub-api/mcp-server/internal/upstream"
 	"github.com/finnhub-api/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// upgradeDowngradeAccess is the plan access get_stock_upgrade-downgrade requires, from swagger.json.
+var upgradeDowngradeAccess = plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}
+
+// upgradeDowngradeListOutputSchema is the output schema for UpgradeDowngrade arrays, derived from swagger.json.
+const upgradeDowngradeListOutputSchema = `{
+  "properties": {
+    "items": {
+      "items": {
+        "properties": {
+          "action": {
+            "description": "Action can take any of the following values: up(upgrade), down(downgrade), main(maintains), init(initiate), reit(reiterate).",
+            "type": ["string", "null"]
+          },
+          "company": {
+            "description": "Company/analyst who did the upgrade/downgrade.",
+            "type": ["string", "null"]
+          },
+          "fromGrade": {
+            "description": "From grade.",
+            "type": ["string", "null"]
+          },
+          "gradeTime": {
+            "description": "Upgrade/downgrade time in UNIX timestamp.",
+            "type": ["integer", "null"]
+          },
+          "symbol": {
+            "description": "Company symbol.",
+            "type": ["string", "null"]
+          },
+          "toGrade": {
+            "description": "To grade.",
+            "type": ["string", "null"]
+          }
+        },
+        "type": ["object", "null"]
+      },
+      "type": ["array", "null"]
+    }
+  },
+  "type": "object"
+}`
+
 func Upgrade_downgradeHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["symbol"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("symbol=%v", val))
-		}
-		if val, ok := args["from"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("from=%v", val))
-		}
-		if val, ok := args["to"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("to=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/stock/upgrade-downgrade%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// No authentication required for this endpoint
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		query := params.Query(args, "symbol", "from", "to")
+		resp, err := upstream.Do(ctx, cfg, upstream.Request{Tool: "get_stock_upgrade-downgrade", Method: "GET", Path: "/stock/upgrade-downgrade", Query: query})
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
 
 		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+			return result.Error(ctx, cfg, "get_stock_upgrade-downgrade", upgradeDowngradeAccess, resp), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return result.JSON(resp.Body), nil
 	}
 }
 
 func CreateUpgrade_downgradeTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_stock_upgrade-downgrade",
-		mcp.WithDescription("Stock Upgrade/Downgrade"),
+		mcp.WithDescription("Get latest stock upgrade and downgrade."),
+		mcp.WithTitleAnnotation("Stock Upgrade/Downgrade"),
+		// Every endpoint looks up Finnhub data without changing it
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithRawOutputSchema([]byte(upgradeDowngradeListOutputSchema)),
 		mcp.WithString("symbol", mcp.Description("Symbol of the company: AAPL. If left blank, the API will return latest stock upgrades/downgrades.")),
-		mcp.WithString("from", mcp.Description("From date: 2000-03-15.")),
-		mcp.WithString("to", mcp.Description("To date: 2020-03-16.")),
+		mcp.WithString("from", mcp.Description("From date: 2000-03-15."), schema.Format("date")),
+		mcp.WithString("to", mcp.Description("To date: 2020-03-16."), schema.Format("date")),
 	)
 
 	return models.Tool{
 		Definition: tool,
-		Handler:    Upgrade_downgradeHandler(cfg),
+		Handler:    schema.Checked(tool, Upgrade_downgradeHandler(cfg)),
+		Toolset:    "stock-estimates",
+		Access:     upgradeDowngradeAccess,
 	}
 }
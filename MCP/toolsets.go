@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// checkToolSelection rejects unknown toolset and tool names, so a typo does
+// not silently leave a session without the tools it asked for. index holds
+// every tool, as toolIndex returns.
+func checkToolSelection(sel *config.ToolSelection, index map[string]models.Tool) error {
+	for _, name := range sel.Toolsets {
+		if name != "all" && !slices.ContainsFunc(Toolsets, func(t models.Toolset) bool { return t.Name == name }) {
+			names := make([]string, len(Toolsets))
+			for i, t := range Toolsets {
+				names[i] = t.Name
+			}
+			return fmt.Errorf("unknown toolset %q (available: all, %s)", name, strings.Join(names, ", "))
+		}
+	}
+	for _, name := range slices.Concat(sel.Tools, sel.Exclude) {
+		if _, ok := index[name]; !ok {
+			return fmt.Errorf("unknown tool %q", name)
+		}
+	}
+	return nil
+}
+
+// checkCacheTTLs rejects cache TTLs set for tools that do not exist.
+func checkCacheTTLs(ttls map[string]time.Duration, tools []models.Tool) error {
+	for _, name := range slices.Sorted(maps.Keys(ttls)) {
+		if !slices.ContainsFunc(tools, func(t models.Tool) bool { return t.Definition.Name == name }) {
+			return fmt.Errorf("unknown tool %q", name)
+		}
+	}
+	return nil
+}
+
+// toolIndex maps each tool name to its tool.
+func toolIndex(tools []models.Tool) map[string]models.Tool {
+	index := make(map[string]models.Tool, len(tools))
+	for _, t := range tools {
+		index[t.Definition.Name] = t
+	}
+	return index
+}
+
+// sessionToolFilter hides the tools a session deselected from tools/list,
+// and hides or annotates the tools its plan does not include. Without a
+// session (STDIO mode) the server configuration applies.
+func sessionToolFilter(base *config.APIConfig, index map[string]models.Tool) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		cfg := config.ForContext(ctx, base)
+		var enabled []mcp.Tool
+		for _, t := range tools {
+			info := index[t.Name]
+			if !cfg.ToolEnabled(t.Name, info.Toolset) {
+				continue
+			}
+			switch {
+			case cfg.Plan == plan.Unknown && info.Access.Tier != plan.Free:
+				t.Description += "\n\n" + info.Access.Requirement()
+			case !cfg.Plan.Allows(info.Access.Tier):
+				if cfg.PlanMode == config.PlanModeHide {
+					continue
+				}
+				t.Description += fmt.Sprintf("\n\n%s Not included in the configured %s plan.", info.Access.Requirement(), cfg.Plan)
+			}
+			enabled = append(enabled, t)
+		}
+		return enabled
+	}
+}
+
+// sessionToolGate rejects calls to tools the session deselected or its plan
+// hides, which clients could still call by name.
+func sessionToolGate(base *config.APIConfig, index map[string]models.Tool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := request.Params.Name
+			cfg := config.ForContext(ctx, base)
+			info := index[name]
+			if !cfg.ToolEnabled(name, info.Toolset) {
+				return mcp.NewToolResultError(fmt.Sprintf("Tool %s is not enabled for this session", name)), nil
+			}
+			if cfg.PlanMode == config.PlanModeHide && !cfg.Plan.Allows(info.Access.Tier) {
+				err := &plan.Error{Tool: name, Access: info.Access, Plan: cfg.Plan}
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// sessionToolUsable reports whether a session can call a tool: it did not
+// deselect the tool, and its plan does not hide it.
+func sessionToolUsable(base *config.APIConfig, index map[string]models.Tool) func(ctx context.Context, name string) bool {
+	return func(ctx context.Context, name string) bool {
+		cfg := config.ForContext(ctx, base)
+		info, ok := index[name]
+		return ok && cfg.ToolEnabled(name, info.Toolset) &&
+			(cfg.PlanMode != config.PlanModeHide || cfg.Plan.Allows(info.Access.Tier))
+	}
+}
+
+// sessionPromptFilter hides the prompts whose instructions call a tool the
+// session cannot use, so a prompt never leads the model to a tool it cannot
+// call.
+func sessionPromptFilter(usable func(ctx context.Context, name string) bool, needs map[string][]string) server.PromptFilterFunc {
+	return func(ctx context.Context, prompts []mcp.Prompt) []mcp.Prompt {
+		var enabled []mcp.Prompt
+		for _, p := range prompts {
+			if !slices.ContainsFunc(needs[p.Name], func(name string) bool { return !usable(ctx, name) }) {
+				enabled = append(enabled, p)
+			}
+		}
+		return enabled
+	}
+}
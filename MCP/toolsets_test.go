@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/finnhub-api/mcp-server/config"
+	"github.com/finnhub-api/mcp-server/internal/plan"
+	"github.com/finnhub-api/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var testIndex = map[string]models.Tool{
+	"get_quote":        {Toolset: "stock-price", Access: plan.Access{Tier: plan.Free}},
+	"get_stock_candle": {Toolset: "stock-price", Access: plan.Access{Tier: plan.Premium, Note: "Premium Access Required"}},
+	"get_stock_metric": {Toolset: "stock-fundamentals", Access: plan.Access{Tier: plan.Free}},
+}
+
+func TestCheckToolSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		sel     config.ToolSelection
+		wantErr string
+	}{
+		{"empty", config.ToolSelection{}, ""},
+		{"all", config.ToolSelection{Toolsets: []string{"all"}}, ""},
+		{"known", config.ToolSelection{Toolsets: []string{"stock-price"}, Tools: []string{"get_stock_metric"}, Exclude: []string{"get_quote"}}, ""},
+		{"unknown toolset", config.ToolSelection{Toolsets: []string{"stock-prices"}}, `unknown toolset "stock-prices" (available: all, stock-fundamentals,`},
+		{"unknown tool", config.ToolSelection{Tools: []string{"get_qoute"}}, `unknown tool "get_qoute"`},
+		{"unknown excluded tool", config.ToolSelection{Exclude: []string{"quote"}}, `unknown tool "quote"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkToolSelection(&tt.sel, testIndex)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkToolSelection: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionToolFilter(t *testing.T) {
+	tools := []mcp.Tool{mcp.NewTool("get_quote"), mcp.NewTool("get_stock_candle"), mcp.NewTool("get_stock_metric")}
+	filter := sessionToolFilter(&config.APIConfig{}, testIndex)
+	names := func(ctx context.Context) []string {
+		var names []string
+		for _, t := range filter(ctx, tools) {
+			names = append(names, t.Name)
+		}
+		return names
+	}
+
+	if got := names(context.Background()); len(got) != 3 {
+		t.Errorf("with the server configuration: %q", got)
+	}
+	cfg := &config.APIConfig{
+		Tools:        config.ToolSelection{Exclude: []string{"get_stock_candle"}},
+		SessionTools: &config.ToolSelection{Toolsets: []string{"stock-price"}, Tools: []string{"get_stock_metric"}},
+	}
+	// The session narrows the server's selection but cannot undo exclusions
+	if got := names(config.NewContext(context.Background(), cfg)); !slices.Equal(got, []string{"get_quote", "get_stock_metric"}) {
+		t.Errorf("session tools = %q", got)
+	}
+}
+
+func TestSessionToolGate(t *testing.T) {
+	handler := sessionToolGate(&config.APIConfig{}, testIndex)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	cfg := &config.APIConfig{SessionTools: &config.ToolSelection{Toolsets: []string{"stock-fundamentals"}}}
+	ctx := config.NewContext(context.Background(), cfg)
+
+	for name, allowed := range map[string]bool{"get_stock_metric": true, "get_quote": false} {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		res, err := handler(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.IsError == allowed {
+			t.Errorf("%s: IsError = %v", name, res.IsError)
+		}
+	}
+}
+
+func TestPlanAnnotation(t *testing.T) {
+	tools := []mcp.Tool{
+		mcp.NewTool("get_quote", mcp.WithDescription("Quote")),
+		mcp.NewTool("get_stock_candle", mcp.WithDescription("Candles")),
+	}
+	tests := []struct {
+		name   string
+		cfg    config.APIConfig
+		want   []string
+		candle string // Description of get_stock_candle, if listed
+	}{
+		{"no plan", config.APIConfig{}, []string{"get_quote", "get_stock_candle"},
+			"Candles\n\nRequires the premium plan (Premium Access Required)."},
+		{"hide", config.APIConfig{Plan: "free", PlanMode: config.PlanModeHide}, []string{"get_quote"}, ""},
+		{"annotate", config.APIConfig{Plan: "free", PlanMode: config.PlanModeAnnotate}, []string{"get_quote", "get_stock_candle"},
+			"Candles\n\nRequires the premium plan (Premium Access Required). Not included in the configured free plan."},
+		{"included", config.APIConfig{Plan: "premium"}, []string{"get_quote", "get_stock_candle"}, "Candles"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, tool := range sessionToolFilter(&tt.cfg, testIndex)(context.Background(), tools) {
+				names = append(names, tool.Name)
+				if tool.Name == "get_quote" && tool.Description != "Quote" {
+					t.Errorf("free tool annotated: %q", tool.Description)
+				}
+				if tool.Name == "get_stock_candle" && tool.Description != tt.candle {
+					t.Errorf("description = %q, want %q", tool.Description, tt.candle)
+				}
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("listed %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanGate(t *testing.T) {
+	handler := sessionToolGate(&config.APIConfig{Plan: "free", PlanMode: config.PlanModeHide}, testIndex)(
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_stock_candle"
+	res, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := res.Content[0].(mcp.TextContent).Text
+	if !res.IsError || !strings.Contains(text, "requires the premium Finnhub plan") || !strings.Contains(text, "configured free plan does not include") {
+		t.Errorf("hidden tool called: %q", text)
+	}
+}
+
+// TestPlanTools lists the paid tools each plan includes, from the tiers of
+// the generated tools.
+func TestPlanTools(t *testing.T) {
+	fundamental := []string{"get_airline_price-index", "get_bank-branch", "get_economic", "get_economic_code"}
+	fundamental2 := []string{"get_stock_historical-employee-count", "get_stock_historical-market-cap"}
+	enterprise := []string{"get_stock_international-filings"}
+	notPremium := slices.Concat(fundamental, fundamental2, enterprise)
+
+	tools := GetAll(&config.APIConfig{})
+	free := 0
+	for _, tool := range tools {
+		if tool.Access.Tier == plan.Free {
+			free++
+		}
+	}
+	for _, tt := range []struct {
+		plan     plan.Plan
+		allowed  func(name string) bool
+		wantPaid int
+	}{
+		{"free", func(string) bool { return false }, 0},
+		{"premium", func(name string) bool { return !slices.Contains(notPremium, name) }, 72},
+		{"fundamental", func(name string) bool { return slices.Contains(fundamental, name) }, len(fundamental)},
+		{"fundamental-2", func(name string) bool { return slices.Contains(fundamental2, name) }, len(fundamental2)},
+		{"all-in-one", func(name string) bool { return !slices.Contains(enterprise, name) }, len(tools) - free - len(enterprise)},
+		{"enterprise", func(string) bool { return true }, len(tools) - free},
+	} {
+		t.Run(string(tt.plan), func(t *testing.T) {
+			paid := 0
+			for _, tool := range tools {
+				name, tier := tool.Definition.Name, tool.Access.Tier
+				if tier == plan.Free {
+					if !tt.plan.Allows(tier) {
+						t.Errorf("%s excludes free tool %s", tt.plan, name)
+					}
+					continue
+				}
+				want := tt.allowed(name)
+				if got := tt.plan.Allows(tier); got != want {
+					t.Errorf("%s allows %s (%s) = %v, want %v", tt.plan, name, tier, got, want)
+				}
+				if want {
+					paid++
+				}
+			}
+			if paid != tt.wantPaid {
+				t.Errorf("%s includes %d paid tools, want %d", tt.plan, paid, tt.wantPaid)
+			}
+		})
+	}
+}